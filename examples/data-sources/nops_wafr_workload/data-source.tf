@@ -0,0 +1,12 @@
+data "nops_wafr_workload" "checkout" {
+  project_id = nops_wafr_workload.checkout.project_id
+  id         = nops_wafr_workload.checkout.id
+}
+
+# Fails the plan while the production workload has high risks left.
+check "checkout_well_architected" {
+  assert {
+    condition     = data.nops_wafr_workload.checkout.high_risk_count == 0
+    error_message = "The checkout workload has ${data.nops_wafr_workload.checkout.high_risk_count} high risks in its Well-Architected review."
+  }
+}
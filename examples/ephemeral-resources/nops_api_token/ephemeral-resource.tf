@@ -0,0 +1,9 @@
+# Exchanges the API key of the default provider for a token revoked at the end of the run.
+ephemeral "nops_api_token" "run" {
+  expires_in = 900
+}
+
+provider "nops" {
+  alias        = "short_lived"
+  nops_api_key = ephemeral.nops_api_token.run.token
+}
@@ -0,0 +1,11 @@
+ephemeral "nops_external_id" "project" {
+  project_id = nops_project.project.id
+}
+
+# Ephemeral values can only be used in write-only arguments, provider configurations and other ephemeral resources.
+resource "aws_ssm_parameter" "nops_external_id" {
+  name             = "/nops/external-id"
+  type             = "SecureString"
+  value_wo         = ephemeral.nops_external_id.project.external_id
+  value_wo_version = 1
+}
@@ -0,0 +1,4 @@
+resource "aws_iam_role" "nops_integration_role" {
+  name = provider::nops::integration_role_name(nops_project.project.client_id)
+  # ...
+}
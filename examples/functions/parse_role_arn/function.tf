@@ -0,0 +1,4 @@
+output "integration_role_name" {
+  # Returns an object with the partition, account_id, path and role_name attributes.
+  value = provider::nops::parse_role_arn(nops_integration.integration.role_arn).role_name
+}
@@ -0,0 +1,3 @@
+resource "aws_s3_bucket" "nops_system_bucket" {
+  bucket = provider::nops::system_bucket_name(nops_project.project.client_id, nops_project.project.project_id, data.aws_caller_identity.current.account_id)
+}
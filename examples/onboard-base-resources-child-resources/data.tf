@@ -2,4 +2,6 @@
 
 data "aws_caller_identity" "current" {}
 
+data "aws_partition" "current" {}
+
 data "aws_organizations_organization" "current" {}
@@ -2,7 +2,7 @@
 
 resource "aws_s3_bucket" "nops_system_bucket" {
   count         = local.create_bucket ? 1 : 0
-  bucket        = local.system_bucket_name
+  bucket        = var.system_bucket_name != "na" ? var.system_bucket_name : nops_project.project.system_bucket_name
   force_destroy = true
 
   lifecycle {
@@ -34,23 +34,5 @@ resource "aws_s3_bucket_public_access_block" "nops_bucket_block_public_access" {
 resource "aws_s3_bucket_policy" "nops_bucket_policy" {
   count  = local.create_bucket ? 1 : 0
   bucket = aws_s3_bucket.nops_system_bucket[0].id
-  policy = jsonencode({
-    Version = "2008-10-17"
-    Statement = [
-      {
-        Effect    = "Deny"
-        Principal = "*"
-        Action    = "s3:*"
-        Resource = [
-          aws_s3_bucket.nops_system_bucket[0].arn,
-          "${aws_s3_bucket.nops_system_bucket[0].arn}/*"
-        ]
-        Condition = {
-          Bool = {
-            "aws:SecureTransport" = "false"
-          }
-        }
-      }
-    ]
-  })
+  policy = nops_system_bucket.bucket[0].bucket_policy
 }
@@ -1,7 +1,7 @@
 
 
 resource "aws_iam_role" "nops_integration_role" {
-  name = "NopsIntegrationRole-${local.client_id}"
+  name = nops_project.project.integration_role_name
 
   assume_role_policy = jsonencode({
     Version = "2012-10-17"
@@ -170,7 +170,7 @@ resource "aws_iam_role_policy" "nops_integration_policy" {
 }
 
 resource "aws_iam_role_policy" "nops_system_bucket_policy" {
-  count = local.is_master_account && local.system_bucket_name != "na" ? 1 : 0
+  count = local.create_bucket ? 1 : 0
   name  = "NopsSystemBucketPolicy"
   role  = aws_iam_role.nops_integration_role.id
 
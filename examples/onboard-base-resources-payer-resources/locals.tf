@@ -5,10 +5,8 @@ locals {
   nops_url           = "https://app.nops.io/"
   account_id         = data.aws_caller_identity.current.account_id
   master_account_id  = data.aws_organizations_organization.current.master_account_id
-  is_master_account  = local.account_id == local.master_account_id
-  client_id          = nops_project.project.id
-  project_id         = nops_project.project.client
+  is_master_account  = provider::nops::is_payer(local.account_id, local.master_account_id)
   external_id        = nops_project.project.external_id
-  system_bucket_name = var.system_bucket_name != "na" ? var.system_bucket_name : "nops-${local.client_id}-${local.project_id}-${local.account_id}"
+  system_bucket_name = local.create_bucket ? nops_system_bucket.bucket[0].bucket_name : "na"
   create_bucket      = local.is_master_account
 }
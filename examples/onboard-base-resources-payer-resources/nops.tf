@@ -8,13 +8,32 @@ resource "nops_project" "project" {
   master_payer_account_number = data.aws_organizations_organization.current.master_account_id
 }
 
+# Registers the system bucket with nOps, which computes its policy and verifies it can read it.
+resource "nops_system_bucket" "bucket" {
+  count      = local.create_bucket ? 1 : 0
+  project_id = nops_project.project.id
+  partition  = data.aws_partition.current.partition
+  # Registers the bucket under the name it was created with, S3 buckets created by earlier versions of this
+  # example are named nops-<project_id>-<client_id>-<account_id> and keep that name.
+  bucket_name = aws_s3_bucket.nops_system_bucket[0].id
+}
+
 resource "nops_integration" "integration" {
   role_arn       = aws_iam_role.nops_integration_role.arn
-  external_id    = local.external_id
   aws_account_id = local.account_id
   bucket_name    = local.is_master_account ? local.system_bucket_name : "na"
+  project_id     = nops_project.project.id
+  # nOps validates the role and bucket as soon as it is notified, so they must be fully configured first.
+  # The notification is retried while the new role propagates in IAM.
   depends_on = [
-    time_sleep.wait_for_resources
+    aws_iam_role_policy.nops_integration_policy,
+    aws_iam_role_policy.nops_system_bucket_policy,
+    aws_iam_role_policy.nops_essentials_policy,
+    aws_iam_role_policy.nops_compute_copilot_policy,
+    aws_iam_role_policy.nops_wafr_policy,
+    aws_s3_bucket.nops_system_bucket,
+    aws_s3_bucket_policy.nops_bucket_policy,
+    aws_s3_bucket_server_side_encryption_configuration.nops_bucket_encryption
   ]
 }
 
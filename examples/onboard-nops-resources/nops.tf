@@ -18,12 +18,10 @@ resource "nops_project" "project" {
 
 resource "nops_integration" "integration" {
   role_arn       = "arn:aws:iam::xxxxx:role/na"
-  external_id    = "NOPS-xxxxxx"
   aws_account_id = "xxxx"
   bucket_name    = "na"
-  depends_on = [
-    nops_project.project
-  ]
+  # Targets the project directly, the external ID defaults to the one generated for it by nOps
+  project_id = nops_project.project.id
 }
 
 data "nops_projects" "this" {}
@@ -0,0 +1,44 @@
+resource "nops_azure_project" "project" {
+  name            = "azure-production"
+  tenant_id       = data.azurerm_client_config.current.tenant_id
+  subscription_id = data.azurerm_client_config.current.subscription_id
+}
+
+resource "azuread_application" "nops" {
+  display_name = "nops-integration"
+}
+
+resource "azuread_service_principal" "nops" {
+  client_id = azuread_application.nops.client_id
+}
+
+resource "azurerm_role_assignment" "cost_management_reader" {
+  scope                = nops_azure_project.project.scope
+  role_definition_name = "Cost Management Reader"
+  principal_id         = azuread_service_principal.nops.object_id
+}
+
+resource "azurerm_role_assignment" "exports_reader" {
+  scope                = azurerm_storage_account.exports.id
+  role_definition_name = "Storage Blob Data Reader"
+  principal_id         = azuread_service_principal.nops.object_id
+}
+
+# nOps authenticates with a federated credential it issues, no client secret is shared.
+resource "nops_azure_integration" "integration" {
+  project_id         = nops_azure_project.project.id
+  application_id     = azuread_application.nops.client_id
+  storage_account_id = azurerm_storage_account.exports.id
+  storage_container  = "cost-exports"
+  export_directory   = "nops"
+
+  depends_on = [azurerm_role_assignment.cost_management_reader, azurerm_role_assignment.exports_reader]
+}
+
+resource "azuread_application_federated_identity_credential" "nops" {
+  application_id = azuread_application.nops.id
+  display_name   = "nops"
+  audiences      = ["api://AzureADTokenExchange"]
+  issuer         = nops_azure_integration.integration.federated_issuer
+  subject        = nops_azure_integration.integration.federated_subject
+}
@@ -0,0 +1,15 @@
+data "azurerm_client_config" "current" {}
+
+# Onboards a subscription to nOps.
+resource "nops_azure_project" "subscription" {
+  name            = "azure-production"
+  tenant_id       = data.azurerm_client_config.current.tenant_id
+  subscription_id = data.azurerm_client_config.current.subscription_id
+}
+
+# Onboards every subscription billed to an Enterprise Agreement enrollment or a Microsoft Customer Agreement.
+resource "nops_azure_project" "billing_account" {
+  name               = "azure-billing"
+  tenant_id          = data.azurerm_client_config.current.tenant_id
+  billing_account_id = "12345678"
+}
@@ -0,0 +1,8 @@
+# Commitments are purchased by the payer account and shared with its linked accounts.
+resource "nops_commitment_management" "payer" {
+  project_id        = nops_project.payer.id
+  risk_tolerance    = "balanced"
+  term_lengths      = [1]
+  payment_options   = ["no_upfront", "partial_upfront"]
+  excluded_services = ["AmazonRDS"]
+}
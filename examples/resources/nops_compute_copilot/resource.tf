@@ -0,0 +1,17 @@
+# Copilot runs the web and worker groups on spot, keeping one on-demand instance in each of them.
+resource "nops_compute_copilot" "production" {
+  project_id              = nops_project.project.id
+  auto_scaling_groups     = ["web", "workers"]
+  spot_percentage         = 80
+  on_demand_base_capacity = 1
+
+  eks_node_groups = [
+    {
+      cluster_name    = "production"
+      node_group_name = "general"
+    },
+  ]
+
+  # The project role needs the Compute Copilot policy before nOps can manage the targets.
+  depends_on = [aws_iam_role_policy.nops_compute_copilot_policy]
+}
@@ -0,0 +1,9 @@
+# nOps activates the keys in the payer account through its integration role.
+resource "nops_cost_allocation_tags" "payer" {
+  project_id = nops_project.payer.id
+  tag_keys   = ["team", "cost-center", "environment"]
+}
+
+output "inactive_cost_allocation_tags" {
+  value = [for key, status in nops_cost_allocation_tags.payer.tag_statuses : key if status != "active"]
+}
@@ -0,0 +1,19 @@
+resource "nops_project" "project" {
+  name                        = "payer"
+  account_number              = data.aws_organizations_organization.current.master_account_id
+  master_payer_account_number = data.aws_organizations_organization.current.master_account_id
+}
+
+# Tells nOps which report to ingest from the system bucket of the payer account.
+resource "nops_cur_configuration" "cur" {
+  project_id  = nops_project.project.id
+  bucket_name = aws_s3_bucket.nops_system_bucket.id
+  prefix      = "reports"
+  report_name = "nops-cur-2"
+  # One of cur_legacy, cur_2_0 or focus
+  format = "cur_2_0"
+}
+
+output "cur_ingestion_status" {
+  value = nops_cur_configuration.cur.status
+}
@@ -0,0 +1,23 @@
+data "aws_caller_identity" "current" {}
+
+data "aws_eks_cluster" "production" {
+  name = "production"
+}
+
+resource "nops_eks_cluster_integration" "production" {
+  project_id   = nops_project.project.id
+  account_id   = data.aws_caller_identity.current.account_id
+  region       = "us-east-1"
+  cluster_name = data.aws_eks_cluster.production.name
+}
+
+# Installs the cost agent with the values nOps generated for the cluster.
+resource "helm_release" "nops_agent" {
+  name             = "nops-agent"
+  repository       = "https://nops-io.github.io/nops-k8s-agent"
+  chart            = "nops-k8s-agent"
+  namespace        = "nops"
+  create_namespace = true
+
+  values = [nops_eks_cluster_integration.production.helm_values]
+}
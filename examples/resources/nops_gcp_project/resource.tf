@@ -0,0 +1,41 @@
+resource "google_service_account" "nops" {
+  project      = "nops-billing"
+  account_id   = "nops-reader"
+  display_name = "nOps billing export reader"
+}
+
+resource "google_project_iam_member" "nops" {
+  for_each = toset(["roles/bigquery.dataViewer", "roles/bigquery.jobUser"])
+
+  project = "nops-billing"
+  role    = each.value
+  member  = "serviceAccount:${google_service_account.nops.email}"
+}
+
+# nOps impersonates the service account through workload identity federation, no key is shared.
+resource "nops_gcp_project" "billing" {
+  name                = "gcp-billing"
+  billing_account_id  = "012345-6789AB-CDEF01"
+  bigquery_project_id = "nops-billing"
+  bigquery_dataset_id = "billing_export"
+
+  workload_identity_federation = {
+    workload_identity_provider = "projects/123456789012/locations/global/workloadIdentityPools/nops/providers/nops-aws"
+    service_account_email      = google_service_account.nops.email
+  }
+
+  depends_on = [google_project_iam_member.nops]
+}
+
+resource "google_service_account_key" "nops" {
+  service_account_id = google_service_account.nops.name
+}
+
+# Authenticates with a service account key instead.
+resource "nops_gcp_project" "billing_with_key" {
+  name                = "gcp-billing-key"
+  billing_account_id  = "FEDCBA-987654-321012"
+  bigquery_project_id = "nops-billing"
+  bigquery_dataset_id = "billing_export"
+  service_account_key = base64decode(google_service_account_key.nops.private_key)
+}
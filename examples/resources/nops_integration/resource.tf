@@ -13,11 +13,9 @@ resource "nops_project" "project" {
 resource "nops_integration" "integration" {
   # Role with sufficient permissions for nOps to get cost and metadata
   role_arn       = aws_iam_role.nops_integration_role.arn
-  external_id    = nops_project.project.external_id
   aws_account_id = data.aws_caller_identity.current.account_id
   # If being deployed in a management account set S3 bucket name, if not value should be "na"
   bucket_name = aws_s3_bucket.nops_system_bucket.id
-  depends_on = [
-    nops_project.project
-  ]
+  # Targets the project directly, the external ID defaults to the one generated for it by nOps
+  project_id = nops_project.project.id
 }
\ No newline at end of file
@@ -0,0 +1,18 @@
+data "aws_organizations_organization" "current" {}
+
+# Onboards every member account of the organization to the nOps platform, one project per account.
+# Accounts added to or removed from the organization are onboarded or deleted on the next apply.
+resource "nops_organization_onboarding" "organization" {
+  master_payer_account_number = data.aws_organizations_organization.current.master_account_id
+  accounts = {
+    for account in data.aws_organizations_organization.current.non_master_accounts : account.id => account.name
+    if account.status == "ACTIVE"
+  }
+}
+
+output "pending_integration" {
+  value = [
+    for account, project in nops_organization_onboarding.organization.projects : account
+    if project.status == "pending_integration"
+  ]
+}
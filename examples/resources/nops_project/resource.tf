@@ -8,4 +8,23 @@ resource "nops_project" "project" {
   name                        = "project"
   account_number              = data.aws_caller_identity.current.account_id
   master_payer_account_number = data.aws_organizations_organization.current.master_account_id
+  deletion_protection         = true
+}
+
+# Takes ownership of a project already integrated with nOps for the account instead of failing,
+# and leaves it in place when removed from the configuration.
+resource "nops_project" "existing" {
+  name                        = "existing-project"
+  account_number              = "123456789012"
+  master_payer_account_number = data.aws_organizations_organization.current.master_account_id
+  adopt_existing              = true
+  retain_on_destroy           = true
+}
+
+# Onboards a linked account in the same apply as its payer, the payer project is created first.
+resource "nops_project" "linked" {
+  name                        = "linked-project"
+  account_number              = "210987654321"
+  master_payer_account_number = nops_project.project.account_number
+  payer_project_id            = nops_project.project.id
 }
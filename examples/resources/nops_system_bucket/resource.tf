@@ -0,0 +1,25 @@
+resource "nops_project" "project" {
+  name                        = "payer"
+  account_number              = data.aws_organizations_organization.current.master_account_id
+  master_payer_account_number = data.aws_organizations_organization.current.master_account_id
+}
+
+# Registers the system bucket of the payer project, its name and policy are computed by nOps conventions.
+resource "nops_system_bucket" "bucket" {
+  project_id = nops_project.project.id
+  partition  = data.aws_partition.current.partition
+}
+
+resource "aws_s3_bucket" "nops_system_bucket" {
+  bucket = nops_system_bucket.bucket.bucket_name
+}
+
+resource "aws_s3_bucket_policy" "nops_bucket_policy" {
+  bucket = aws_s3_bucket.nops_system_bucket.id
+  policy = nops_system_bucket.bucket.bucket_policy
+}
+
+# nOps verifies it can read the bucket once the integration role is in place, refresh to see the outcome.
+output "system_bucket_verified" {
+  value = nops_system_bucket.bucket.verified
+}
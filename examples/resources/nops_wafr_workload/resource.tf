@@ -0,0 +1,11 @@
+resource "nops_wafr_workload" "checkout" {
+  project_id  = nops_project.project.id
+  name        = "checkout"
+  environment = "production"
+  regions     = ["us-east-1", "eu-west-1"]
+  lenses      = ["wellarchitected", "serverless"]
+  owners      = ["platform@example.com"]
+
+  # The project role needs the WAFR policy before nOps can define the workload.
+  depends_on = [aws_iam_role_policy.nops_wafr_policy]
+}
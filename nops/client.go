@@ -1,22 +1,61 @@
 package nops
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // HostURL - Default nOps URL.
 const HostURL string = "https://app.nops.io"
 
+// DefaultMaxConcurrency - Default number of in flight requests for bulk operations.
+const DefaultMaxConcurrency int = 5
+
+// maxRequestAttempts - Number of times a request is sent before giving up on 429 responses.
+const maxRequestAttempts int = 4
+
+// maxNotifyAttempts - Number of times nOps is notified of an integration while it can't assume the role.
+const maxNotifyAttempts int = 6
+
+// defaultNotifyRetryDelay - Time waited before notifying nOps again of an integration it couldn't assume the role of.
+const defaultNotifyRetryDelay = 10 * time.Second
+
 // Client - HTTP client to be used by the provider.
 type Client struct {
 	HostURL    string
 	HTTPClient *http.Client
 	Auth       AuthStruct
+	// MaxConcurrency bounds the number of requests issued at the same time by bulk operations,
+	// it is shared by every resource using the client and must be set before the first bulk call.
+	MaxConcurrency int
+	// Limiter is shared by every request sent by the client, requests aren't rate limited when nil.
+	Limiter *RateLimiter
+	// DriftReport makes resources warn about attributes changed in nOps outside of Terraform.
+	DriftReport bool
+
+	slots            chan struct{}
+	slotsOnce        sync.Once
+	noBulkSupport    atomic.Bool
+	notifyRetryDelay time.Duration
+}
+
+// APIError - unsuccessful response returned by the nOps APIs.
+type APIError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("status: %d, body: %s", e.StatusCode, e.Body)
 }
 
 // AuthStruct - authentication mechanism with an API Key.
@@ -31,7 +70,9 @@ func NewClient(host, api_key *string) (*Client, error) {
 			Timeout: 10 * time.Second,
 		},
 		// Default nOps URL
-		HostURL: HostURL,
+		HostURL:          HostURL,
+		MaxConcurrency:   DefaultMaxConcurrency,
+		notifyRetryDelay: defaultNotifyRetryDelay,
 	}
 
 	if host != nil {
@@ -55,28 +96,56 @@ func (c *Client) doRequest(req *http.Request) ([]byte, error) {
 	req.Header.Set("X-Nops-Api-Key", token)
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
+	for attempt := 1; ; attempt++ {
+		if err := c.Limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
 
-	defer res.Body.Close()
+		res, err := c.HTTPClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		// Requests rejected by the nOps rate limit are retried once the client slowed down.
+		if res.StatusCode == http.StatusTooManyRequests && attempt < maxRequestAttempts && (req.Body == nil || req.GetBody != nil) {
+			c.Limiter.Throttled(req.Context(), retryAfter(res))
+			if req.GetBody != nil {
+				req.Body, err = req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		statusOK := res.StatusCode >= 200 && res.StatusCode < 300
+		if !statusOK {
+			return nil, &APIError{StatusCode: res.StatusCode, Body: body}
+		}
+		c.Limiter.Succeeded(req.Context())
+
+		return body, err
 	}
+}
 
-	statusOK := res.StatusCode >= 200 && res.StatusCode < 300
-	if !statusOK {
-		return nil, fmt.Errorf("status: %d, body: %s", res.StatusCode, body)
+// retryAfter parses the Retry-After header in seconds, defaulting to one second.
+func retryAfter(res *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(res.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return time.Second
 	}
 
-	return body, err
+	return time.Duration(seconds) * time.Second
 }
 
-func (c *Client) GetProjects() ([]Project, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/c/admin/projectaws/", c.HostURL), nil)
+func (c *Client) GetProjects(ctx context.Context) ([]Project, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/c/admin/projectaws/", c.HostURL), nil)
 
 	if err != nil {
 		return nil, err
@@ -96,13 +165,13 @@ func (c *Client) GetProjects() ([]Project, error) {
 	return projects, nil
 }
 
-func (c *Client) CreateProject(project NewProject) (*Project, error) {
+func (c *Client) CreateProject(ctx context.Context, project NewProject) (*Project, error) {
 	rb, err := json.Marshal(project)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/c/admin/projectaws/", c.HostURL), strings.NewReader(string(rb)))
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/c/admin/projectaws/", c.HostURL), strings.NewReader(string(rb)))
 	if err != nil {
 		return nil, err
 	}
@@ -121,13 +190,13 @@ func (c *Client) CreateProject(project NewProject) (*Project, error) {
 	return &projects, nil
 }
 
-func (c *Client) UpdateProject(id int64, project UpdateProject) (*Project, error) {
+func (c *Client) UpdateProject(ctx context.Context, id int64, project UpdateProject) (*Project, error) {
 	rb, err := json.Marshal(project)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("PATCH", fmt.Sprintf("%s/c/admin/projectaws/%d/", c.HostURL, id), strings.NewReader(string(rb)))
+	req, err := http.NewRequestWithContext(ctx, "PATCH", fmt.Sprintf("%s/c/admin/projectaws/%d/", c.HostURL, id), strings.NewReader(string(rb)))
 
 	if err != nil {
 		return nil, err
@@ -147,9 +216,9 @@ func (c *Client) UpdateProject(id int64, project UpdateProject) (*Project, error
 	return &projects, nil
 }
 
-func (c *Client) DeleteProject(id int64) error {
+func (c *Client) DeleteProject(ctx context.Context, id int64) error {
 
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/c/admin/projectaws/%d/", c.HostURL, id), nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/c/admin/projectaws/%d/", c.HostURL, id), nil)
 	if err != nil {
 		return err
 	}
@@ -162,14 +231,94 @@ func (c *Client) DeleteProject(id int64) error {
 	return nil
 }
 
-func (c *Client) NotifyNops(payload Integration) (*IntegrationResponse, error) {
+// NotifyNops links the role of the account to its project. nOps assumes the role right away, so it's notified
+// again while the role can't be assumed yet, as new IAM roles take a few seconds to propagate.
+func (c *Client) NotifyNops(ctx context.Context, payload Integration) (*IntegrationResponse, error) {
 	rb, err := json.Marshal(payload)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/c/aws/integration/", c.HostURL), strings.NewReader(string(rb)))
-	req.Header.Set("X-Aws-Account-Number", payload.AccountNumber)
+	var body []byte
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/c/aws/integration/", c.HostURL), strings.NewReader(string(rb)))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("X-Aws-Account-Number", payload.AccountNumber)
+
+		body, err = c.doRequest(req)
+		if err == nil {
+			break
+		}
+		if attempt >= maxNotifyAttempts || !isAssumeRoleError(err) {
+			return nil, err
+		}
+
+		timer := time.NewTimer(c.notifyRetryDelay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+
+	status := IntegrationResponse{}
+	err = json.Unmarshal(body, &status)
+	if err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// isAssumeRoleError reports whether nOps rejected an integration because it couldn't assume the role.
+func isAssumeRoleError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || (apiErr.StatusCode != http.StatusBadRequest && apiErr.StatusCode != http.StatusForbidden) {
+		return false
+	}
+
+	return strings.Contains(strings.ToLower(string(apiErr.Body)), "assumerole")
+}
+
+// IsNotFound reports whether the error is a 404 response from the nOps APIs.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// GetCURConfiguration returns the cost report configuration of a project, nOps answers 404 when none is configured.
+func (c *Client) GetCURConfiguration(ctx context.Context, projectID int64) (*CURConfiguration, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/c/admin/projectaws/%d/cur_configuration/", c.HostURL, projectID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	configuration := CURConfiguration{}
+	err = json.Unmarshal(body, &configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	return &configuration, nil
+}
+
+// PutCURConfiguration creates or replaces the cost report configuration of a project. nOps validates the
+// configuration before accepting it and starts ingesting the reports asynchronously.
+func (c *Client) PutCURConfiguration(ctx context.Context, projectID int64, configuration CURConfiguration) (*CURConfiguration, error) {
+	rb, err := json.Marshal(configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/c/admin/projectaws/%d/cur_configuration/", c.HostURL, projectID), strings.NewReader(string(rb)))
 	if err != nil {
 		return nil, err
 	}
@@ -179,11 +328,834 @@ func (c *Client) NotifyNops(payload Integration) (*IntegrationResponse, error) {
 		return nil, err
 	}
 
-	status := IntegrationResponse{}
-	err = json.Unmarshal(body, &status)
+	updated := CURConfiguration{}
+	err = json.Unmarshal(body, &updated)
 	if err != nil {
 		return nil, err
 	}
 
-	return &status, nil
+	return &updated, nil
+}
+
+// DeleteCURConfiguration stops nOps from ingesting the cost reports of a project.
+func (c *Client) DeleteCURConfiguration(ctx context.Context, projectID int64) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/c/admin/projectaws/%d/cur_configuration/", c.HostURL, projectID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req)
+	return err
+}
+
+// GetSystemBucket returns the system bucket registered for a project, nOps answers 404 when none is registered.
+func (c *Client) GetSystemBucket(ctx context.Context, projectID int64) (*SystemBucket, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/c/admin/projectaws/%d/system_bucket/", c.HostURL, projectID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket := SystemBucket{}
+	err = json.Unmarshal(body, &bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bucket, nil
+}
+
+// PutSystemBucket registers the system bucket of a project, nOps then verifies it can read the bucket through
+// the integration role and reports the outcome in the bucket status.
+func (c *Client) PutSystemBucket(ctx context.Context, projectID int64, bucket SystemBucket) (*SystemBucket, error) {
+	rb, err := json.Marshal(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/c/admin/projectaws/%d/system_bucket/", c.HostURL, projectID), strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	registered := SystemBucket{}
+	err = json.Unmarshal(body, &registered)
+	if err != nil {
+		return nil, err
+	}
+
+	return &registered, nil
+}
+
+// DeleteSystemBucket unregisters the system bucket of a project, the bucket itself is left untouched.
+func (c *Client) DeleteSystemBucket(ctx context.Context, projectID int64) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/c/admin/projectaws/%d/system_bucket/", c.HostURL, projectID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req)
+	return err
+}
+
+// GetEKSCluster returns an EKS cluster registered with a project, nOps answers 404 when it isn't registered.
+func (c *Client) GetEKSCluster(ctx context.Context, projectID, clusterID int64) (*EKSCluster, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/c/admin/projectaws/%d/eks_clusters/%d/", c.HostURL, projectID, clusterID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster := EKSCluster{}
+	err = json.Unmarshal(body, &cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cluster, nil
+}
+
+// CreateEKSCluster registers an EKS cluster with a project, nOps returns the token and Helm values
+// the cost agent is installed with.
+func (c *Client) CreateEKSCluster(ctx context.Context, projectID int64, cluster NewEKSCluster) (*EKSCluster, error) {
+	rb, err := json.Marshal(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/c/admin/projectaws/%d/eks_clusters/", c.HostURL, projectID), strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	created := EKSCluster{}
+	err = json.Unmarshal(body, &created)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// DeleteEKSCluster unregisters an EKS cluster, its agent token is revoked.
+func (c *Client) DeleteEKSCluster(ctx context.Context, projectID, clusterID int64) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/c/admin/projectaws/%d/eks_clusters/%d/", c.HostURL, projectID, clusterID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req)
+	return err
+}
+
+// GetComputeCopilot returns the Compute Copilot settings of a project, nOps answers 404 when Copilot was never configured.
+func (c *Client) GetComputeCopilot(ctx context.Context, projectID int64) (*ComputeCopilot, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/c/admin/projectaws/%d/compute_copilot/", c.HostURL, projectID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	copilot := ComputeCopilot{}
+	err = json.Unmarshal(body, &copilot)
+	if err != nil {
+		return nil, err
+	}
+
+	return &copilot, nil
+}
+
+// PutComputeCopilot replaces the Compute Copilot settings of a project. nOps checks the targeted Auto Scaling
+// Groups and node groups exist through the integration role and reports the outcome in the settings status.
+func (c *Client) PutComputeCopilot(ctx context.Context, projectID int64, copilot ComputeCopilot) (*ComputeCopilot, error) {
+	rb, err := json.Marshal(copilot)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/c/admin/projectaws/%d/compute_copilot/", c.HostURL, projectID), strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := ComputeCopilot{}
+	err = json.Unmarshal(body, &updated)
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// DeleteComputeCopilot disables Compute Copilot for a project and releases its targets back to their own settings.
+func (c *Client) DeleteComputeCopilot(ctx context.Context, projectID int64) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/c/admin/projectaws/%d/compute_copilot/", c.HostURL, projectID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req)
+	return err
+}
+
+// GetCommitmentManagement returns the commitment management settings of a payer project, nOps answers 404
+// when they were never configured.
+func (c *Client) GetCommitmentManagement(ctx context.Context, projectID int64) (*CommitmentManagement, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/c/admin/projectaws/%d/commitment_management/", c.HostURL, projectID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := CommitmentManagement{}
+	err = json.Unmarshal(body, &settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+// PutCommitmentManagement replaces the commitment management settings of a payer project, nOps applies them
+// to the commitments it purchases from then on.
+func (c *Client) PutCommitmentManagement(ctx context.Context, projectID int64, settings CommitmentManagement) (*CommitmentManagement, error) {
+	rb, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/c/admin/projectaws/%d/commitment_management/", c.HostURL, projectID), strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := CommitmentManagement{}
+	err = json.Unmarshal(body, &updated)
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// DeleteCommitmentManagement stops nOps from managing the commitments of a payer project, the commitments
+// already purchased are kept until they expire.
+func (c *Client) DeleteCommitmentManagement(ctx context.Context, projectID int64) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/c/admin/projectaws/%d/commitment_management/", c.HostURL, projectID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req)
+	return err
+}
+
+// GetWAFRWorkload returns a Well-Architected workload of a project with its risk counts, nOps answers 404
+// when it doesn't exist.
+func (c *Client) GetWAFRWorkload(ctx context.Context, projectID, workloadID int64) (*WAFRWorkload, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/c/admin/projectaws/%d/wafr_workloads/%d/", c.HostURL, projectID, workloadID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	workload := WAFRWorkload{}
+	err = json.Unmarshal(body, &workload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &workload, nil
+}
+
+// CreateWAFRWorkload creates a Well-Architected workload for a project, nOps defines it in the Well-Architected Tool
+// of the account and starts reviewing it.
+func (c *Client) CreateWAFRWorkload(ctx context.Context, projectID int64, workload NewWAFRWorkload) (*WAFRWorkload, error) {
+	rb, err := json.Marshal(workload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/c/admin/projectaws/%d/wafr_workloads/", c.HostURL, projectID), strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	created := WAFRWorkload{}
+	err = json.Unmarshal(body, &created)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// UpdateWAFRWorkload updates a Well-Architected workload of a project.
+func (c *Client) UpdateWAFRWorkload(ctx context.Context, projectID, workloadID int64, workload NewWAFRWorkload) (*WAFRWorkload, error) {
+	rb, err := json.Marshal(workload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", fmt.Sprintf("%s/c/admin/projectaws/%d/wafr_workloads/%d/", c.HostURL, projectID, workloadID), strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := WAFRWorkload{}
+	err = json.Unmarshal(body, &updated)
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// DeleteWAFRWorkload deletes a Well-Architected workload and its reviews.
+func (c *Client) DeleteWAFRWorkload(ctx context.Context, projectID, workloadID int64) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/c/admin/projectaws/%d/wafr_workloads/%d/", c.HostURL, projectID, workloadID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req)
+	return err
+}
+
+// GetCostAllocationTags returns the cost allocation tag keys nOps activates for a payer project along with the
+// status of every user-defined tag key of the account, nOps answers 404 when none is managed.
+func (c *Client) GetCostAllocationTags(ctx context.Context, projectID int64) (*CostAllocationTags, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/c/admin/projectaws/%d/cost_allocation_tags/", c.HostURL, projectID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := CostAllocationTags{}
+	err = json.Unmarshal(body, &tags)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tags, nil
+}
+
+// PutCostAllocationTags replaces the cost allocation tag keys nOps activates for a payer project. nOps activates
+// them through the integration role and deactivates the keys it activated before that aren't listed anymore.
+func (c *Client) PutCostAllocationTags(ctx context.Context, projectID int64, tags CostAllocationTags) (*CostAllocationTags, error) {
+	rb, err := json.Marshal(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/c/admin/projectaws/%d/cost_allocation_tags/", c.HostURL, projectID), strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := CostAllocationTags{}
+	err = json.Unmarshal(body, &updated)
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// DeleteCostAllocationTags deactivates the cost allocation tag keys nOps activated for a payer project.
+func (c *Client) DeleteCostAllocationTags(ctx context.Context, projectID int64) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/c/admin/projectaws/%d/cost_allocation_tags/", c.HostURL, projectID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req)
+	return err
+}
+
+// GetGCPProject returns the GCP project with the ID, nOps answers 404 when it doesn't exist.
+// Service account keys are never returned.
+func (c *Client) GetGCPProject(ctx context.Context, id int64) (*GCPProject, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/c/admin/projectgcp/%d/", c.HostURL, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	project := GCPProject{}
+	err = json.Unmarshal(body, &project)
+	if err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+// CreateGCPProject onboards a GCP billing account to nOps, its costs are read from the BigQuery billing export.
+func (c *Client) CreateGCPProject(ctx context.Context, project NewGCPProject) (*GCPProject, error) {
+	rb, err := json.Marshal(project)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/c/admin/projectgcp/", c.HostURL), strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	created := GCPProject{}
+	err = json.Unmarshal(body, &created)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// UpdateGCPProject updates the name, the billing export and the credentials of a GCP project.
+func (c *Client) UpdateGCPProject(ctx context.Context, id int64, project UpdateGCPProject) (*GCPProject, error) {
+	rb, err := json.Marshal(project)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", fmt.Sprintf("%s/c/admin/projectgcp/%d/", c.HostURL, id), strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := GCPProject{}
+	err = json.Unmarshal(body, &updated)
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// DeleteGCPProject removes a GCP project and its cost history from nOps.
+func (c *Client) DeleteGCPProject(ctx context.Context, id int64) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/c/admin/projectgcp/%d/", c.HostURL, id), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req)
+	return err
+}
+
+// GetAzureProject returns the Azure project with the ID, nOps answers 404 when it doesn't exist.
+func (c *Client) GetAzureProject(ctx context.Context, id int64) (*AzureProject, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/c/admin/projectazure/%d/", c.HostURL, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	project := AzureProject{}
+	err = json.Unmarshal(body, &project)
+	if err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+// CreateAzureProject onboards an Azure subscription or billing account to nOps.
+func (c *Client) CreateAzureProject(ctx context.Context, project NewAzureProject) (*AzureProject, error) {
+	rb, err := json.Marshal(project)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/c/admin/projectazure/", c.HostURL), strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	created := AzureProject{}
+	err = json.Unmarshal(body, &created)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// UpdateAzureProject updates the name of an Azure project, its scope can't be changed.
+func (c *Client) UpdateAzureProject(ctx context.Context, id int64, project UpdateAzureProject) (*AzureProject, error) {
+	rb, err := json.Marshal(project)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", fmt.Sprintf("%s/c/admin/projectazure/%d/", c.HostURL, id), strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := AzureProject{}
+	err = json.Unmarshal(body, &updated)
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// DeleteAzureProject removes an Azure project and its cost history from nOps.
+func (c *Client) DeleteAzureProject(ctx context.Context, id int64) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/c/admin/projectazure/%d/", c.HostURL, id), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req)
+	return err
+}
+
+// GetAzureIntegration returns the integration of an Azure project, nOps answers 404 when none is configured.
+// The client secret of service principals is never returned.
+func (c *Client) GetAzureIntegration(ctx context.Context, projectID int64) (*AzureIntegration, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/c/admin/projectazure/%d/integration/", c.HostURL, projectID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	integration := AzureIntegration{}
+	err = json.Unmarshal(body, &integration)
+	if err != nil {
+		return nil, err
+	}
+
+	return &integration, nil
+}
+
+// PutAzureIntegration creates or replaces the integration of an Azure project. nOps authenticates with the client
+// secret when it is set, and with a federated credential issued by nOps otherwise.
+func (c *Client) PutAzureIntegration(ctx context.Context, projectID int64, integration AzureIntegration) (*AzureIntegration, error) {
+	rb, err := json.Marshal(integration)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/c/admin/projectazure/%d/integration/", c.HostURL, projectID), strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := AzureIntegration{}
+	err = json.Unmarshal(body, &updated)
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// DeleteAzureIntegration stops nOps from accessing the Azure scope and cost exports of a project.
+func (c *Client) DeleteAzureIntegration(ctx context.Context, projectID int64) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/c/admin/projectazure/%d/integration/", c.HostURL, projectID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req)
+	return err
+}
+
+// CreateAPIToken exchanges the API key of the client for a short-lived API token expiring after the requested lifetime.
+func (c *Client) CreateAPIToken(ctx context.Context, token NewAPIToken) (*APIToken, error) {
+	rb, err := json.Marshal(token)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/c/admin/api_tokens/", c.HostURL), strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	created := APIToken{}
+	err = json.Unmarshal(body, &created)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// DeleteAPIToken revokes an API token before it expires, nOps answers 404 when it already expired.
+func (c *Client) DeleteAPIToken(ctx context.Context, id int64) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/c/admin/api_tokens/%d/", c.HostURL, id), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req)
+	return err
+}
+
+// ProjectResult - outcome of a single item of a bulk project operation.
+type ProjectResult struct {
+	Project *Project
+	Err     error
+}
+
+// CreateProjects creates the projects using the bulk endpoint, falling back to concurrent single requests
+// when the nOps host doesn't support it. Results are returned in the same order as the input.
+func (c *Client) CreateProjects(ctx context.Context, projects []NewProject) []ProjectResult {
+	if results, ok := c.bulkRequest(ctx, "POST", len(projects), projects); ok {
+		return results
+	}
+
+	return c.forEachProject(ctx, len(projects), func(i int) (*Project, error) {
+		return c.CreateProject(ctx, projects[i])
+	})
+}
+
+// UpdateProjects updates the projects using the bulk endpoint, falling back to concurrent single requests
+// when the nOps host doesn't support it. Results are returned in the same order as the input.
+func (c *Client) UpdateProjects(ctx context.Context, projects []BulkUpdateProject) []ProjectResult {
+	if results, ok := c.bulkRequest(ctx, "PATCH", len(projects), projects); ok {
+		return results
+	}
+
+	return c.forEachProject(ctx, len(projects), func(i int) (*Project, error) {
+		return c.UpdateProject(ctx, projects[i].ID, projects[i].UpdateProject)
+	})
+}
+
+// DeleteProjects deletes the projects concurrently, returning one error per project in the same order as the input.
+func (c *Client) DeleteProjects(ctx context.Context, ids []int64) []error {
+	results := c.forEachProject(ctx, len(ids), func(i int) (*Project, error) {
+		return nil, c.DeleteProject(ctx, ids[i])
+	})
+
+	errs := make([]error, len(results))
+	for i, result := range results {
+		errs[i] = result.Err
+	}
+
+	return errs
+}
+
+// bulkRequest sends every item in a single request to the bulk endpoint, the response holds the outcome of each
+// item in the same order. It returns false when the endpoint isn't available on the nOps host, in which case the
+// caller falls back to single requests. Any other failure is returned for every item: nOps may have applied the
+// batch already, and sending the items again could create the projects twice.
+func (c *Client) bulkRequest(ctx context.Context, method string, count int, payload any) ([]ProjectResult, bool) {
+	if count == 0 {
+		return []ProjectResult{}, true
+	}
+	if c.noBulkSupport.Load() {
+		return nil, false
+	}
+
+	rb, err := json.Marshal(payload)
+	if err != nil {
+		return bulkFailure(count, err), true
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/c/admin/projectaws/bulk/", c.HostURL), strings.NewReader(string(rb)))
+	if err != nil {
+		return bulkFailure(count, err), true
+	}
+
+	if err := c.acquireSlot(ctx); err != nil {
+		return bulkFailure(count, err), true
+	}
+	body, err := c.doRequest(req)
+	c.releaseSlot()
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusMethodNotAllowed) {
+		c.noBulkSupport.Store(true)
+		return nil, false
+	}
+	if err != nil {
+		return bulkFailure(count, err), true
+	}
+
+	items := []BulkProjectResult{}
+	err = json.Unmarshal(body, &items)
+	if err != nil {
+		return bulkFailure(count, fmt.Errorf("invalid bulk response: %w", err)), true
+	}
+	if len(items) != count {
+		return bulkFailure(count, fmt.Errorf("invalid bulk response: expected %d items, got %d", count, len(items))), true
+	}
+
+	results := make([]ProjectResult, count)
+	for i := range items {
+		if items[i].Error != "" {
+			results[i].Err = fmt.Errorf("bulk item %d failed: %s", i, items[i].Error)
+			continue
+		}
+		results[i].Project = &items[i].Project
+	}
+
+	return results, true
+}
+
+// bulkFailure returns the error as the outcome of every item of a failed bulk request.
+func bulkFailure(count int, err error) []ProjectResult {
+	results := make([]ProjectResult, count)
+	for i := range results {
+		results[i].Err = err
+	}
+
+	return results
+}
+
+// forEachProject runs the operation for every index, bounded by the client concurrency limit. Operations that
+// didn't start when the context is canceled fail with the context error.
+func (c *Client) forEachProject(ctx context.Context, count int, operation func(i int) (*Project, error)) []ProjectResult {
+	results := make([]ProjectResult, count)
+
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := c.acquireSlot(ctx); err != nil {
+				results[i] = ProjectResult{Err: err}
+				return
+			}
+			defer c.releaseSlot()
+
+			project, err := operation(i)
+			results[i] = ProjectResult{Project: project, Err: err}
+		}(i)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// acquireSlot waits for a free slot of the concurrency limit, returning the context error when it is canceled first.
+func (c *Client) acquireSlot(ctx context.Context) error {
+	c.slotsOnce.Do(func() {
+		size := c.MaxConcurrency
+		if size < 1 {
+			size = DefaultMaxConcurrency
+		}
+		c.slots = make(chan struct{}, size)
+	})
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	select {
+	case c.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Client) releaseSlot() {
+	<-c.slots
 }
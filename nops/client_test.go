@@ -0,0 +1,303 @@
+package nops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	host := server.URL
+	apiKey := "test"
+	client, err := NewClient(&host, &apiKey)
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %s", err)
+	}
+
+	return client
+}
+
+func TestClientCreateProjectsBulkEndpoint(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/c/admin/projectaws/bulk/" || r.Method != "POST" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		var projects []NewProject
+		if err := json.NewDecoder(r.Body).Decode(&projects); err != nil {
+			t.Errorf("unexpected error decoding request: %s", err)
+		}
+
+		created := make([]Project, len(projects))
+		for i, project := range projects {
+			created[i] = Project{ID: i + 1, Name: project.Name, AccountNumber: project.AccountNumber}
+		}
+		_ = json.NewEncoder(w).Encode(created)
+	})
+
+	results := client.CreateProjects(context.Background(), []NewProject{
+		{Name: "first", AccountNumber: "111111111111"},
+		{Name: "second", AccountNumber: "222222222222"},
+	})
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	for i, result := range results {
+		if result.Err != nil {
+			t.Errorf("unexpected error for item %d: %s", i, result.Err)
+			continue
+		}
+		if result.Project.ID != i+1 {
+			t.Errorf("expected project ID %d for item %d, got %d", i+1, i, result.Project.ID)
+		}
+	}
+}
+
+func TestClientCreateProjectsFallback(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/c/admin/projectaws/bulk/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			seen := maxInFlight.Load()
+			if current <= seen || maxInFlight.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+
+		var project NewProject
+		if err := json.NewDecoder(r.Body).Decode(&project); err != nil {
+			t.Errorf("unexpected error decoding request: %s", err)
+		}
+		if project.AccountNumber == "000000000000" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"detail":"invalid account"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(Project{ID: 1, Name: project.Name, AccountNumber: project.AccountNumber})
+	})
+	client.MaxConcurrency = 2
+
+	projects := []NewProject{
+		{Name: "first", AccountNumber: "111111111111"},
+		{Name: "invalid", AccountNumber: "000000000000"},
+		{Name: "third", AccountNumber: "333333333333"},
+		{Name: "fourth", AccountNumber: "444444444444"},
+	}
+	results := client.CreateProjects(context.Background(), projects)
+
+	for i, result := range results {
+		if projects[i].AccountNumber == "000000000000" {
+			if result.Err == nil {
+				t.Errorf("expected an error for item %d", i)
+			}
+			continue
+		}
+		if result.Err != nil {
+			t.Errorf("unexpected error for item %d: %s", i, result.Err)
+			continue
+		}
+		if result.Project.Name != projects[i].Name {
+			t.Errorf("expected project %q for item %d, got %q", projects[i].Name, i, result.Project.Name)
+		}
+	}
+
+	if maxInFlight.Load() > 2 {
+		t.Errorf("expected at most 2 concurrent requests, got %d", maxInFlight.Load())
+	}
+	if !client.noBulkSupport.Load() {
+		t.Errorf("expected the bulk endpoint to be marked as unsupported")
+	}
+}
+
+func TestClientUpdateProjectsBulkError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	results := client.UpdateProjects(context.Background(), []BulkUpdateProject{
+		{ID: 1, UpdateProject: UpdateProject{Name: "first", AccountNumber: "111111111111"}},
+		{ID: 2, UpdateProject: UpdateProject{Name: "second", AccountNumber: "222222222222"}},
+	})
+
+	for i, result := range results {
+		if result.Err == nil {
+			t.Errorf("expected an error for item %d", i)
+		}
+	}
+}
+
+func TestClientCreateProjectsBulkPartialFailure(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/c/admin/projectaws/bulk/" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusMultiStatus)
+		_, _ = w.Write([]byte(`[{"id": 1, "name": "first", "account_number": "111111111111"}, {"error": "invalid account"}]`))
+	})
+
+	results := client.CreateProjects(context.Background(), []NewProject{
+		{Name: "first", AccountNumber: "111111111111"},
+		{Name: "invalid", AccountNumber: "000000000000"},
+	})
+
+	if results[0].Err != nil || results[0].Project.ID != 1 {
+		t.Errorf("expected project 1 to be created, got %+v", results[0])
+	}
+	if results[1].Err == nil || results[1].Project != nil {
+		t.Errorf("expected an error for the invalid account, got %+v", results[1])
+	}
+}
+
+func TestClientCreateProjectsBulkFailure(t *testing.T) {
+	var singleRequests atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/c/admin/projectaws/bulk/" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		singleRequests.Add(1)
+		_ = json.NewEncoder(w).Encode(Project{ID: 1})
+	})
+
+	results := client.CreateProjects(context.Background(), []NewProject{
+		{Name: "first", AccountNumber: "111111111111"},
+		{Name: "second", AccountNumber: "222222222222"},
+	})
+
+	// nOps may have created the projects before failing, they aren't sent again.
+	for i, result := range results {
+		if result.Err == nil {
+			t.Errorf("expected the bulk error for item %d", i)
+		}
+	}
+	if singleRequests.Load() != 0 {
+		t.Errorf("expected no single requests after a bulk failure, got %d", singleRequests.Load())
+	}
+	if client.noBulkSupport.Load() {
+		t.Errorf("expected the bulk endpoint to be used on the next call")
+	}
+}
+
+func TestClientDeleteProjectsCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var requests atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// The first request cancels the apply while the other deletions wait for a slot.
+		requests.Add(1)
+		cancel()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	client.MaxConcurrency = 1
+
+	errs := client.DeleteProjects(ctx, []int64{1, 2, 3})
+
+	canceled := 0
+	for _, err := range errs {
+		if errors.Is(err, context.Canceled) {
+			canceled++
+		}
+	}
+	if requests.Load() != 1 || canceled < 2 {
+		t.Errorf("expected the queued deletions to fail with the context error, got %d requests and errors %v", requests.Load(), errs)
+	}
+}
+
+func TestClientRetriesTooManyRequests(t *testing.T) {
+	var attempts atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var project NewProject
+		if err := json.NewDecoder(r.Body).Decode(&project); err != nil {
+			t.Errorf("unexpected error decoding retried request: %s", err)
+		}
+		if attempts.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(Project{ID: 1, Name: project.Name, AccountNumber: project.AccountNumber})
+	})
+	client.Limiter = NewRateLimiter(100, 1)
+
+	project, err := client.CreateProject(context.Background(), NewProject{Name: "retried", AccountNumber: "111111111111"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if project.Name != "retried" {
+		t.Errorf("expected project %q, got %q", "retried", project.Name)
+	}
+	if attempts.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts.Load())
+	}
+	if client.Limiter.throttled != 2 {
+		t.Errorf("expected 2 throttled responses, got %d", client.Limiter.throttled)
+	}
+}
+
+func TestClientTooManyRequestsGivesUp(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	_, err := client.GetProjects(context.Background())
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected a 429 API error, got %v", err)
+	}
+}
+
+func TestClientNotifyNopsRetriesAssumeRole(t *testing.T) {
+	var attempts atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var integration Integration
+		if err := json.NewDecoder(r.Body).Decode(&integration); err != nil {
+			t.Errorf("unexpected error decoding retried request: %s", err)
+		}
+		attempt := attempts.Add(1)
+		switch {
+		case integration.AccountNumber == "000000000000":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"detail":"invalid account"}`))
+		case attempt < 3:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"detail":"An error occurred (AccessDenied) when calling the AssumeRole operation"}`))
+		default:
+			_ = json.NewEncoder(w).Encode(IntegrationResponse{Status: "ok"})
+		}
+	})
+	client.notifyRetryDelay = time.Millisecond
+
+	status, err := client.NotifyNops(context.Background(), Integration{AccountNumber: "111111111111", RoleArn: "arn:aws:iam::111111111111:role/NopsIntegrationRole"})
+	if err != nil {
+		t.Fatalf("expected the notification to succeed once the role can be assumed, got %s", err)
+	}
+	if status.Status != "ok" || attempts.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d with status %q", attempts.Load(), status.Status)
+	}
+
+	attempts.Store(0)
+	if _, err := client.NotifyNops(context.Background(), Integration{AccountNumber: "000000000000"}); err == nil {
+		t.Errorf("expected an error for the invalid account")
+	}
+	if attempts.Load() != 1 {
+		t.Errorf("expected other errors not to be retried, got %d attempts", attempts.Load())
+	}
+}
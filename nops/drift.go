@@ -0,0 +1,68 @@
+package nops
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// attributeDrift is an attribute changed in nOps outside of Terraform.
+type attributeDrift struct {
+	Attribute string
+	Before    string
+	After     string
+}
+
+// driftDetector collects the attributes changed in nOps while refreshing a resource.
+type driftDetector struct {
+	changes []attributeDrift
+}
+
+// refresh returns the upstream value for the attribute, recording a change when it differs from the state.
+// Attributes that were never set in state, such as right after an import, aren't reported.
+func (d *driftDetector) refresh(attribute string, current types.String, upstream string) types.String {
+	if !current.IsNull() && !current.IsUnknown() && current.ValueString() != upstream {
+		d.changes = append(d.changes, attributeDrift{Attribute: attribute, Before: current.ValueString(), After: upstream})
+	}
+
+	return types.StringValue(upstream)
+}
+
+// refreshSensitive is refresh for sensitive attributes, the drift is reported without the values.
+func (d *driftDetector) refreshSensitive(attribute string, current types.String, upstream string) types.String {
+	if !current.IsNull() && !current.IsUnknown() && current.ValueString() != upstream {
+		d.changes = append(d.changes, attributeDrift{Attribute: attribute, Before: "(sensitive value)", After: "(sensitive value)"})
+	}
+
+	return types.StringValue(upstream)
+}
+
+// report logs the changes and, when the drift report is enabled in the provider, adds a warning summarizing them.
+// updatedBy and updatedAt are the author and time of the last change reported by nOps, empty when unknown.
+func (d *driftDetector) report(ctx context.Context, diags *diag.Diagnostics, enabled bool, subject string, updatedBy, updatedAt string) {
+	if len(d.changes) == 0 {
+		return
+	}
+
+	var summary strings.Builder
+	summary.WriteString("The following attributes were changed in nOps outside of Terraform")
+	if updatedBy != "" {
+		summary.WriteString(" by " + updatedBy)
+	}
+	if updatedAt != "" {
+		summary.WriteString(" at " + serverTimestamp(updatedAt).ValueString())
+	}
+	summary.WriteString(":\n")
+	for _, change := range d.changes {
+		summary.WriteString(fmt.Sprintf("\n  - %s: %q -> %q", change.Attribute, change.Before, change.After))
+	}
+
+	tflog.Info(ctx, subject+" changed outside of Terraform", map[string]any{"changes": d.changes, "updated_by": updatedBy})
+	if enabled {
+		diags.AddWarning(subject+" changed outside of Terraform", summary.String())
+	}
+}
@@ -0,0 +1,747 @@
+package nops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	fwschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// fakeNopsTimestamp is the time reported by the fake nOps APIs for every change.
+const fakeNopsTimestamp = "2024-10-21T15:04:05.123456Z"
+
+// fakeNops is an in-memory implementation of the nOps project APIs used by unit tests.
+type fakeNops struct {
+	mu                sync.Mutex
+	projects          map[int]Project
+	curConfigurations map[int]CURConfiguration
+	systemBuckets     map[int]SystemBucket
+	azureProjects     map[int]AzureProject
+	azureIntegrations map[int]AzureIntegration
+	gcpProjects       map[int]GCPProject
+	eksClusters       map[int]EKSCluster
+	computeCopilots   map[int]ComputeCopilot
+	commitments       map[int]CommitmentManagement
+	wafrWorkloads     map[int]WAFRWorkload
+	costTags          map[int]CostAllocationTags
+	apiTokens         map[int]APIToken
+	integrations      []Integration
+	nextID            int
+	requests          []string
+	// failures answers the requests, keyed by method and path, with an error status.
+	failures map[string]int
+}
+
+// testResourceState builds a Terraform state, plan or config value of a resource holding the model.
+func testResourceState(t *testing.T, s fwschema.Schema, model any) tfsdk.State {
+	t.Helper()
+
+	state := nullResourceState(s)
+	if diags := state.Set(context.Background(), model); diags.HasError() {
+		t.Fatalf("unexpected error building state: %v", diags)
+	}
+
+	return state
+}
+
+// newTestResource configures the resource with a client of the fake nOps APIs and returns it with its schema.
+func newTestResource[R fwresource.ResourceWithConfigure](t *testing.T, fake *fakeNops, r R) (R, fwschema.Schema) {
+	t.Helper()
+
+	var configureResp fwresource.ConfigureResponse
+	r.Configure(context.Background(), fwresource.ConfigureRequest{ProviderData: fake.client(t)}, &configureResp)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error configuring resource: %v", configureResp.Diagnostics)
+	}
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(context.Background(), fwresource.SchemaRequest{}, &schemaResp)
+
+	return r, schemaResp.Schema
+}
+
+// nullResourceState returns the state of a resource that doesn't exist yet, such as before create or import.
+func nullResourceState(s fwschema.Schema) tfsdk.State {
+	return tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(context.Background()), nil)}
+}
+
+func newFakeNops(projects ...Project) *fakeNops {
+	f := &fakeNops{
+		projects:          map[int]Project{},
+		curConfigurations: map[int]CURConfiguration{},
+		systemBuckets:     map[int]SystemBucket{},
+		azureProjects:     map[int]AzureProject{},
+		azureIntegrations: map[int]AzureIntegration{},
+		gcpProjects:       map[int]GCPProject{},
+		eksClusters:       map[int]EKSCluster{},
+		computeCopilots:   map[int]ComputeCopilot{},
+		commitments:       map[int]CommitmentManagement{},
+		wafrWorkloads:     map[int]WAFRWorkload{},
+		costTags:          map[int]CostAllocationTags{},
+		apiTokens:         map[int]APIToken{},
+		nextID:            1,
+		failures:          map[string]int{},
+	}
+	for _, project := range projects {
+		f.projects[project.ID] = project
+		if project.ID >= f.nextID {
+			f.nextID = project.ID + 1
+		}
+	}
+
+	return f
+}
+
+// client starts the fake server and returns a client pointing at it.
+func (f *fakeNops) client(t *testing.T) *Client {
+	t.Helper()
+
+	return newTestClient(t, f.ServeHTTP)
+}
+
+// project returns the stored project with the ID, if any.
+func (f *fakeNops) project(id int) (Project, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	project, ok := f.projects[id]
+	return project, ok
+}
+
+// requested reports whether a request was received with the method and path.
+func (f *fakeNops) requested(method, path string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, request := range f.requests {
+		if request == method+" "+path {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *fakeNops) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	if status, ok := f.failures[r.Method+" "+r.URL.Path]; ok {
+		f.write(w, status, map[string]string{"detail": http.StatusText(status)})
+		return
+	}
+
+	const projectsPath = "/c/admin/projectaws/"
+	const azureProjectsPath = "/c/admin/projectazure/"
+	const gcpProjectsPath = "/c/admin/projectgcp/"
+	switch {
+	case r.URL.Path == projectsPath && r.Method == "GET":
+		projects := make([]Project, 0, len(f.projects))
+		for _, project := range f.projects {
+			projects = append(projects, project)
+		}
+		sort.Slice(projects, func(i, j int) bool { return projects[i].ID < projects[j].ID })
+		f.write(w, http.StatusOK, projects)
+
+	case r.URL.Path == projectsPath && r.Method == "POST":
+		var newProject NewProject
+		if err := json.NewDecoder(r.Body).Decode(&newProject); err != nil {
+			f.write(w, http.StatusBadRequest, map[string]string{"detail": err.Error()})
+			return
+		}
+		project := Project{
+			ID:                       f.nextID,
+			Client:                   1000,
+			Name:                     newProject.Name,
+			AccountNumber:            newProject.AccountNumber,
+			MasterPayerAccountNumber: newProject.MasterPayerAccountNumber,
+			Arn:                      fmt.Sprintf("arn:aws:iam::%s:role/na", newProject.AccountNumber),
+			Bucket:                   "na",
+			ExternalID:               fmt.Sprintf("external-id-%d", f.nextID),
+			RoleName:                 "na",
+			CreatedAt:                fakeNopsTimestamp,
+			UpdatedAt:                fakeNopsTimestamp,
+		}
+		f.projects[project.ID] = project
+		f.nextID++
+		f.write(w, http.StatusCreated, project)
+
+	case strings.HasPrefix(r.URL.Path, projectsPath) && strings.HasSuffix(r.URL.Path, "/cur_configuration/"):
+		f.serveCURConfiguration(w, r, strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, projectsPath), "/cur_configuration/"))
+
+	case strings.HasPrefix(r.URL.Path, projectsPath) && strings.HasSuffix(r.URL.Path, "/system_bucket/"):
+		f.serveSystemBucket(w, r, strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, projectsPath), "/system_bucket/"))
+
+	case strings.HasPrefix(r.URL.Path, projectsPath) && strings.HasSuffix(r.URL.Path, "/compute_copilot/"):
+		f.serveComputeCopilot(w, r, strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, projectsPath), "/compute_copilot/"))
+
+	case strings.HasPrefix(r.URL.Path, projectsPath) && strings.HasSuffix(r.URL.Path, "/commitment_management/"):
+		f.serveCommitmentManagement(w, r, strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, projectsPath), "/commitment_management/"))
+
+	case strings.HasPrefix(r.URL.Path, projectsPath) && strings.HasSuffix(r.URL.Path, "/cost_allocation_tags/"):
+		f.serveCostAllocationTags(w, r, strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, projectsPath), "/cost_allocation_tags/"))
+
+	case strings.HasPrefix(r.URL.Path, projectsPath) && strings.Contains(r.URL.Path, "/wafr_workloads/"):
+		projectID, workloadID, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, projectsPath), "/wafr_workloads/")
+		f.serveWAFRWorkload(w, r, projectID, strings.Trim(workloadID, "/"))
+
+	case strings.HasPrefix(r.URL.Path, projectsPath) && strings.Contains(r.URL.Path, "/eks_clusters/"):
+		projectID, clusterID, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, projectsPath), "/eks_clusters/")
+		f.serveEKSCluster(w, r, projectID, strings.Trim(clusterID, "/"))
+
+	case strings.HasPrefix(r.URL.Path, projectsPath) && (r.Method == "PATCH" || r.Method == "DELETE"):
+		id, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(r.URL.Path, projectsPath), "/"))
+		project, ok := f.projects[id]
+		if err != nil || !ok {
+			f.write(w, http.StatusNotFound, map[string]string{"detail": "Not found."})
+			return
+		}
+		if r.Method == "DELETE" {
+			delete(f.projects, id)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		var update UpdateProject
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			f.write(w, http.StatusBadRequest, map[string]string{"detail": err.Error()})
+			return
+		}
+		project.Name = update.Name
+		project.AccountNumber = update.AccountNumber
+		project.UpdatedAt = fakeNopsTimestamp
+		f.projects[id] = project
+		f.write(w, http.StatusOK, project)
+
+	case strings.HasPrefix(r.URL.Path, "/c/admin/api_tokens/"):
+		f.serveAPIToken(w, r, strings.Trim(strings.TrimPrefix(r.URL.Path, "/c/admin/api_tokens/"), "/"))
+
+	case strings.HasPrefix(r.URL.Path, azureProjectsPath):
+		f.serveAzureProject(w, r, strings.TrimPrefix(r.URL.Path, azureProjectsPath))
+
+	case strings.HasPrefix(r.URL.Path, gcpProjectsPath):
+		f.serveGCPProject(w, r, strings.TrimPrefix(r.URL.Path, gcpProjectsPath))
+
+	case r.URL.Path == "/c/aws/integration/" && r.Method == "POST":
+		var integration Integration
+		if err := json.NewDecoder(r.Body).Decode(&integration); err != nil {
+			f.write(w, http.StatusBadRequest, map[string]string{"detail": err.Error()})
+			return
+		}
+		f.integrations = append(f.integrations, integration)
+		for id, project := range f.projects {
+			if project.AccountNumber == integration.AccountNumber && (integration.ProjectID == 0 || int64(id) == integration.ProjectID) {
+				project.Arn = integration.RoleArn
+				project.Bucket = integration.BucketName
+				project.RoleName = integration.RoleArn[strings.LastIndex(integration.RoleArn, "/")+1:]
+				project.UpdatedAt = fakeNopsTimestamp
+				project.IntegratedAt = fakeNopsTimestamp
+				f.projects[id] = project
+			}
+		}
+		f.write(w, http.StatusOK, IntegrationResponse{Status: "ok"})
+
+	default:
+		f.write(w, http.StatusNotFound, map[string]string{"detail": "Not found."})
+	}
+}
+
+// serveCURConfiguration implements the cost report configuration APIs of a project, the reports are
+// ingested as soon as they are configured unless the bucket name contains "missing".
+func (f *fakeNops) serveCURConfiguration(w http.ResponseWriter, r *http.Request, projectID string) {
+	id, err := strconv.Atoi(projectID)
+	if _, ok := f.projects[id]; err != nil || !ok {
+		f.write(w, http.StatusNotFound, map[string]string{"detail": "Not found."})
+		return
+	}
+
+	configuration, configured := f.curConfigurations[id]
+	switch r.Method {
+	case "GET":
+		if !configured {
+			f.write(w, http.StatusNotFound, map[string]string{"detail": "Not found."})
+			return
+		}
+		f.write(w, http.StatusOK, configuration)
+
+	case "PUT":
+		if err := json.NewDecoder(r.Body).Decode(&configuration); err != nil {
+			f.write(w, http.StatusBadRequest, map[string]string{"detail": err.Error()})
+			return
+		}
+		configuration.Status = "active"
+		configuration.StatusMessage = ""
+		configuration.LastIngestedAt = fakeNopsTimestamp
+		if strings.Contains(configuration.BucketName, "missing") {
+			configuration.Status = curStatusFailed
+			configuration.StatusMessage = "Access denied reading s3://" + configuration.BucketName
+			configuration.LastIngestedAt = ""
+		}
+		configuration.UpdatedAt = fakeNopsTimestamp
+		f.curConfigurations[id] = configuration
+		f.write(w, http.StatusOK, configuration)
+
+	case "DELETE":
+		delete(f.curConfigurations, id)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// serveSystemBucket implements the system bucket APIs of a project, buckets are verified as soon as they are
+// registered unless their name contains "missing".
+func (f *fakeNops) serveSystemBucket(w http.ResponseWriter, r *http.Request, projectID string) {
+	id, err := strconv.Atoi(projectID)
+	if _, ok := f.projects[id]; err != nil || !ok {
+		f.write(w, http.StatusNotFound, map[string]string{"detail": "Not found."})
+		return
+	}
+
+	bucket, registered := f.systemBuckets[id]
+	switch r.Method {
+	case "GET":
+		if !registered {
+			f.write(w, http.StatusNotFound, map[string]string{"detail": "Not found."})
+			return
+		}
+		f.write(w, http.StatusOK, bucket)
+
+	case "PUT":
+		if err := json.NewDecoder(r.Body).Decode(&bucket); err != nil {
+			f.write(w, http.StatusBadRequest, map[string]string{"detail": err.Error()})
+			return
+		}
+		bucket.Status = systemBucketStatusVerified
+		bucket.StatusMessage = ""
+		bucket.VerifiedAt = fakeNopsTimestamp
+		if strings.Contains(bucket.BucketName, "missing") {
+			bucket.Status = systemBucketStatusFailed
+			bucket.StatusMessage = "Access denied reading s3://" + bucket.BucketName
+		}
+		f.systemBuckets[id] = bucket
+		f.write(w, http.StatusOK, bucket)
+
+	case "DELETE":
+		delete(f.systemBuckets, id)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// serveAzureProject implements the Azure project and integration APIs. Azure projects share their identifiers with
+// the AWS ones, integrations authenticate with a federated credential when no client secret is sent and fail when
+// the container name contains "missing".
+func (f *fakeNops) serveAzureProject(w http.ResponseWriter, r *http.Request, subpath string) {
+	if subpath == "" && r.Method == "POST" {
+		var newProject NewAzureProject
+		if err := json.NewDecoder(r.Body).Decode(&newProject); err != nil {
+			f.write(w, http.StatusBadRequest, map[string]string{"detail": err.Error()})
+			return
+		}
+		project := AzureProject{
+			ID:               f.nextID,
+			Client:           1000,
+			Name:             newProject.Name,
+			TenantID:         newProject.TenantID,
+			SubscriptionID:   newProject.SubscriptionID,
+			BillingAccountID: newProject.BillingAccountID,
+			CreatedAt:        fakeNopsTimestamp,
+			UpdatedAt:        fakeNopsTimestamp,
+		}
+		f.azureProjects[project.ID] = project
+		f.nextID++
+		f.write(w, http.StatusCreated, project)
+		return
+	}
+
+	projectID, integrationPath := strings.CutSuffix(subpath, "integration/")
+	id, err := strconv.Atoi(strings.Trim(projectID, "/"))
+	project, ok := f.azureProjects[id]
+	if err != nil || !ok {
+		f.write(w, http.StatusNotFound, map[string]string{"detail": "Not found."})
+		return
+	}
+
+	if !integrationPath {
+		switch r.Method {
+		case "GET":
+			f.write(w, http.StatusOK, project)
+		case "PATCH":
+			var update UpdateAzureProject
+			if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+				f.write(w, http.StatusBadRequest, map[string]string{"detail": err.Error()})
+				return
+			}
+			project.Name = update.Name
+			project.UpdatedAt = fakeNopsTimestamp
+			f.azureProjects[id] = project
+			f.write(w, http.StatusOK, project)
+		case "DELETE":
+			delete(f.azureProjects, id)
+			delete(f.azureIntegrations, id)
+			w.WriteHeader(http.StatusNoContent)
+		}
+		return
+	}
+
+	integration, configured := f.azureIntegrations[id]
+	switch r.Method {
+	case "GET":
+		if !configured {
+			f.write(w, http.StatusNotFound, map[string]string{"detail": "Not found."})
+			return
+		}
+		f.write(w, http.StatusOK, integration)
+
+	case "PUT":
+		integration = AzureIntegration{}
+		if err := json.NewDecoder(r.Body).Decode(&integration); err != nil {
+			f.write(w, http.StatusBadRequest, map[string]string{"detail": err.Error()})
+			return
+		}
+		integration.AuthenticationType = azureAuthenticationClientSecret
+		integration.Status = "active"
+		if integration.ClientSecret == "" {
+			integration.AuthenticationType = azureAuthenticationFederatedCredential
+			integration.FederatedIssuer = "https://app.nops.io"
+			integration.FederatedSubject = fmt.Sprintf("nops:client:%d:project:%d", project.Client, id)
+		}
+		if strings.Contains(integration.StorageContainer, "missing") {
+			integration.Status = azureIntegrationStatusFailed
+			integration.StatusMessage = "The container " + integration.StorageContainer + " wasn't found"
+		}
+		integration.ClientSecret = ""
+		integration.UpdatedAt = fakeNopsTimestamp
+		project.IntegratedAt = fakeNopsTimestamp
+		f.azureProjects[id] = project
+		f.azureIntegrations[id] = integration
+		f.write(w, http.StatusOK, integration)
+
+	case "DELETE":
+		delete(f.azureIntegrations, id)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// serveGCPProject implements the GCP project APIs. The billing export is read as soon as the project is saved
+// unless the dataset name contains "missing".
+func (f *fakeNops) serveGCPProject(w http.ResponseWriter, r *http.Request, subpath string) {
+	var project GCPProject
+	if subpath == "" && r.Method == "POST" {
+		project = GCPProject{ID: f.nextID, Client: 1000, CreatedAt: fakeNopsTimestamp}
+		f.nextID++
+	} else {
+		id, err := strconv.Atoi(strings.Trim(subpath, "/"))
+		var ok bool
+		project, ok = f.gcpProjects[id]
+		if err != nil || !ok {
+			f.write(w, http.StatusNotFound, map[string]string{"detail": "Not found."})
+			return
+		}
+	}
+
+	switch r.Method {
+	case "GET":
+		f.write(w, http.StatusOK, project)
+
+	case "POST", "PATCH":
+		var update NewGCPProject
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			f.write(w, http.StatusBadRequest, map[string]string{"detail": err.Error()})
+			return
+		}
+		if r.Method == "POST" {
+			project.BillingAccountID = update.BillingAccountID
+			project.BigQueryTableID = "gcp_billing_export_v1_" + strings.ReplaceAll(update.BillingAccountID, "-", "_")
+		}
+		project.Name = update.Name
+		project.BigQueryProjectID = update.BigQueryProjectID
+		project.BigQueryDatasetID = update.BigQueryDatasetID
+		project.AuthenticationType = gcpAuthenticationWorkloadIdentity
+		project.ServiceAccountEmail = update.ServiceAccountEmail
+		project.WorkloadIdentityProvider = update.WorkloadIdentityProvider
+		if update.ServiceAccountKey != "" {
+			project.AuthenticationType = gcpAuthenticationServiceAccountKey
+			project.ServiceAccountEmail, _ = serviceAccountKeyEmail(update.ServiceAccountKey)
+		}
+		project.Status, project.StatusMessage, project.IntegratedAt = "active", "", fakeNopsTimestamp
+		if strings.Contains(project.BigQueryDatasetID, "missing") {
+			project.Status = gcpStatusFailed
+			project.StatusMessage = "Dataset " + project.BigQueryProjectID + ":" + project.BigQueryDatasetID + " was not found"
+			project.IntegratedAt = ""
+		}
+		project.UpdatedAt = fakeNopsTimestamp
+		f.gcpProjects[project.ID] = project
+		f.write(w, http.StatusOK, project)
+
+	case "DELETE":
+		delete(f.gcpProjects, project.ID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// serveComputeCopilot implements the Compute Copilot APIs of a project, the targets are managed as soon as
+// they are configured unless their name contains "missing".
+func (f *fakeNops) serveComputeCopilot(w http.ResponseWriter, r *http.Request, projectID string) {
+	id, err := strconv.Atoi(projectID)
+	if _, ok := f.projects[id]; err != nil || !ok {
+		f.write(w, http.StatusNotFound, map[string]string{"detail": "Not found."})
+		return
+	}
+
+	copilot, configured := f.computeCopilots[id]
+	switch r.Method {
+	case "GET":
+		if !configured {
+			f.write(w, http.StatusNotFound, map[string]string{"detail": "Not found."})
+			return
+		}
+		f.write(w, http.StatusOK, copilot)
+
+	case "PUT":
+		copilot = ComputeCopilot{}
+		if err := json.NewDecoder(r.Body).Decode(&copilot); err != nil {
+			f.write(w, http.StatusBadRequest, map[string]string{"detail": err.Error()})
+			return
+		}
+		copilot.Status, copilot.StatusMessage = "active", ""
+		if !copilot.Enabled {
+			copilot.Status = "paused"
+		}
+		for _, name := range copilot.AutoScalingGroups {
+			if strings.Contains(name, "missing") {
+				copilot.Status = computeCopilotStatusFailed
+				copilot.StatusMessage = "Auto Scaling Group " + name + " was not found"
+			}
+		}
+		copilot.UpdatedAt = fakeNopsTimestamp
+		f.computeCopilots[id] = copilot
+		f.write(w, http.StatusOK, copilot)
+
+	case "DELETE":
+		delete(f.computeCopilots, id)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// serveCommitmentManagement implements the commitment management APIs of a payer project.
+func (f *fakeNops) serveCommitmentManagement(w http.ResponseWriter, r *http.Request, projectID string) {
+	id, err := strconv.Atoi(projectID)
+	if _, ok := f.projects[id]; err != nil || !ok {
+		f.write(w, http.StatusNotFound, map[string]string{"detail": "Not found."})
+		return
+	}
+
+	settings, configured := f.commitments[id]
+	switch r.Method {
+	case "GET":
+		if !configured {
+			f.write(w, http.StatusNotFound, map[string]string{"detail": "Not found."})
+			return
+		}
+		f.write(w, http.StatusOK, settings)
+
+	case "PUT":
+		settings = CommitmentManagement{}
+		if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+			f.write(w, http.StatusBadRequest, map[string]string{"detail": err.Error()})
+			return
+		}
+		settings.UpdatedAt = fakeNopsTimestamp
+		f.commitments[id] = settings
+		f.write(w, http.StatusOK, settings)
+
+	case "DELETE":
+		delete(f.commitments, id)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// serveCostAllocationTags implements the cost allocation tag APIs of a payer project, the keys are activated as
+// soon as they are declared unless their name contains "missing", and the account already has the owner key active.
+func (f *fakeNops) serveCostAllocationTags(w http.ResponseWriter, r *http.Request, projectID string) {
+	id, err := strconv.Atoi(projectID)
+	if _, ok := f.projects[id]; err != nil || !ok {
+		f.write(w, http.StatusNotFound, map[string]string{"detail": "Not found."})
+		return
+	}
+
+	tags, configured := f.costTags[id]
+	switch r.Method {
+	case "GET":
+		if !configured {
+			f.write(w, http.StatusNotFound, map[string]string{"detail": "Not found."})
+			return
+		}
+		f.write(w, http.StatusOK, tags)
+
+	case "PUT":
+		var update CostAllocationTags
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			f.write(w, http.StatusBadRequest, map[string]string{"detail": err.Error()})
+			return
+		}
+		tags = CostAllocationTags{TagKeys: update.TagKeys, Tags: []CostAllocationTag{{Key: "owner", Status: costAllocationTagActive}}}
+		for _, key := range update.TagKeys {
+			tag := CostAllocationTag{Key: key, Status: costAllocationTagActive}
+			if strings.Contains(key, "missing") {
+				tag.Status = costAllocationTagFailed
+				tag.StatusMessage = "Tag key " + key + " was not found in the billing data"
+			}
+			tags.Tags = append(tags.Tags, tag)
+		}
+		tags.UpdatedAt = fakeNopsTimestamp
+		f.costTags[id] = tags
+		f.write(w, http.StatusOK, tags)
+
+	case "DELETE":
+		delete(f.costTags, id)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// serveWAFRWorkload implements the Well-Architected workload APIs of a project, workloads have no risk until
+// tests simulate a review by updating the stored workload.
+func (f *fakeNops) serveWAFRWorkload(w http.ResponseWriter, r *http.Request, projectID, workloadID string) {
+	id, err := strconv.Atoi(projectID)
+	project, ok := f.projects[id]
+	if err != nil || !ok {
+		f.write(w, http.StatusNotFound, map[string]string{"detail": "Not found."})
+		return
+	}
+
+	var workload WAFRWorkload
+	if workloadID == "" && r.Method == "POST" {
+		workload = WAFRWorkload{ID: f.nextID, Project: id, CreatedAt: fakeNopsTimestamp}
+		workload.WorkloadArn = fmt.Sprintf("arn:aws:wellarchitected:us-east-1:%s:workload/%032d", project.AccountNumber, workload.ID)
+		f.nextID++
+	} else {
+		wid, err := strconv.Atoi(workloadID)
+		workload, ok = f.wafrWorkloads[wid]
+		if err != nil || !ok || workload.Project != id {
+			f.write(w, http.StatusNotFound, map[string]string{"detail": "Not found."})
+			return
+		}
+	}
+
+	switch r.Method {
+	case "GET":
+		f.write(w, http.StatusOK, workload)
+
+	case "POST", "PATCH":
+		var update NewWAFRWorkload
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			f.write(w, http.StatusBadRequest, map[string]string{"detail": err.Error()})
+			return
+		}
+		workload.Name = update.Name
+		workload.Environment = update.Environment
+		workload.Regions = update.Regions
+		workload.Lenses = update.Lenses
+		workload.Owners = update.Owners
+		workload.UpdatedAt = fakeNopsTimestamp
+		f.wafrWorkloads[workload.ID] = workload
+		f.write(w, http.StatusOK, workload)
+
+	case "DELETE":
+		delete(f.wafrWorkloads, workload.ID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// serveEKSCluster implements the EKS cluster APIs of a project, registered clusters wait for the
+// heartbeat of their agent, which tests simulate by updating the stored cluster.
+func (f *fakeNops) serveEKSCluster(w http.ResponseWriter, r *http.Request, projectID, clusterID string) {
+	id, err := strconv.Atoi(projectID)
+	project, ok := f.projects[id]
+	if err != nil || !ok {
+		f.write(w, http.StatusNotFound, map[string]string{"detail": "Not found."})
+		return
+	}
+
+	if clusterID == "" && r.Method == "POST" {
+		var newCluster NewEKSCluster
+		if err := json.NewDecoder(r.Body).Decode(&newCluster); err != nil {
+			f.write(w, http.StatusBadRequest, map[string]string{"detail": err.Error()})
+			return
+		}
+		if newCluster.AccountNumber != project.AccountNumber {
+			f.write(w, http.StatusBadRequest, map[string]string{"detail": "The cluster account doesn't match the project."})
+			return
+		}
+		token := fmt.Sprintf("nops-agent-token-%d", f.nextID)
+		cluster := EKSCluster{
+			ID:            f.nextID,
+			ClusterName:   newCluster.ClusterName,
+			Region:        newCluster.Region,
+			AccountNumber: newCluster.AccountNumber,
+			AgentToken:    token,
+			HelmValues:    fmt.Sprintf("clusterName: %s\nregion: %s\nagentToken: %s\n", newCluster.ClusterName, newCluster.Region, token),
+			AgentStatus:   eksAgentStatusPending,
+			CreatedAt:     fakeNopsTimestamp,
+			UpdatedAt:     fakeNopsTimestamp,
+		}
+		f.eksClusters[cluster.ID] = cluster
+		f.nextID++
+		f.write(w, http.StatusCreated, cluster)
+		return
+	}
+
+	cid, err := strconv.Atoi(clusterID)
+	cluster, ok := f.eksClusters[cid]
+	if err != nil || !ok || cluster.AccountNumber != project.AccountNumber {
+		f.write(w, http.StatusNotFound, map[string]string{"detail": "Not found."})
+		return
+	}
+	switch r.Method {
+	case "GET":
+		f.write(w, http.StatusOK, cluster)
+
+	case "DELETE":
+		delete(f.eksClusters, cid)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		f.write(w, http.StatusMethodNotAllowed, map[string]string{"detail": "Method not allowed."})
+	}
+}
+
+// serveAPIToken implements the API token exchange, tokens are created on the collection and revoked by ID.
+func (f *fakeNops) serveAPIToken(w http.ResponseWriter, r *http.Request, tokenID string) {
+	switch {
+	case tokenID == "" && r.Method == "POST":
+		var newToken NewAPIToken
+		if err := json.NewDecoder(r.Body).Decode(&newToken); err != nil {
+			f.write(w, http.StatusBadRequest, map[string]string{"detail": err.Error()})
+			return
+		}
+		token := APIToken{ID: f.nextID, Token: fmt.Sprintf("token-%d-%d", f.nextID, newToken.ExpiresIn), ExpiresAt: fakeNopsTimestamp}
+		f.apiTokens[token.ID] = token
+		f.nextID++
+		f.write(w, http.StatusCreated, token)
+
+	case r.Method == "DELETE":
+		id, err := strconv.Atoi(tokenID)
+		if _, ok := f.apiTokens[id]; err != nil || !ok {
+			f.write(w, http.StatusNotFound, map[string]string{"detail": "Not found."})
+			return
+		}
+		delete(f.apiTokens, id)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		f.write(w, http.StatusNotFound, map[string]string{"detail": "Not found."})
+	}
+}
+
+func (f *fakeNops) write(w http.ResponseWriter, status int, body any) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,29 @@
+package nops
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// accountIDPattern matches a 12 digit AWS account ID.
+var accountIDPattern = regexp.MustCompile(`^\d{12}$`)
+
+// validateAccountIDArgument returns an argument error when the value isn't an AWS account ID.
+func validateAccountIDArgument(position int64, name, value string) *function.FuncError {
+	if !accountIDPattern.MatchString(value) {
+		return function.NewArgumentFuncError(position, fmt.Sprintf("%s must be a 12 digit AWS account ID, got %q", name, value))
+	}
+
+	return nil
+}
+
+// validateIDArgument returns an argument error when the value isn't a valid nOps identifier.
+func validateIDArgument(position int64, name string, value int64) *function.FuncError {
+	if value <= 0 {
+		return function.NewArgumentFuncError(position, fmt.Sprintf("%s must be a positive nOps identifier, got %d", name, value))
+	}
+
+	return nil
+}
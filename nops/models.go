@@ -1,14 +1,19 @@
 package nops
 
 type Project struct {
-	ID            int    `json:"id"`
-	Client        int    `json:"client"`
-	Arn           string `json:"arn"`
-	Bucket        string `json:"bucket"`
-	AccountNumber string `json:"account_number"`
-	Name          string `json:"name"`
-	ExternalID    string `json:"external_id"`
-	RoleName      string `json:"role_name"`
+	ID                       int    `json:"id"`
+	Client                   int    `json:"client"`
+	Arn                      string `json:"arn"`
+	Bucket                   string `json:"bucket"`
+	AccountNumber            string `json:"account_number"`
+	MasterPayerAccountNumber string `json:"master_payer_account_number,omitempty"`
+	Name                     string `json:"name"`
+	ExternalID               string `json:"external_id"`
+	RoleName                 string `json:"role_name"`
+	CreatedAt                string `json:"created_at"`
+	UpdatedAt                string `json:"updated_at"`
+	IntegratedAt             string `json:"integrated_at"`
+	UpdatedBy                string `json:"updated_by"`
 }
 
 type NewProject struct {
@@ -22,12 +27,55 @@ type UpdateProject struct {
 	AccountNumber string `json:"account_number"`
 }
 
+type GCPProject struct {
+	ID                       int    `json:"id"`
+	Client                   int    `json:"client"`
+	Name                     string `json:"name"`
+	BillingAccountID         string `json:"billing_account_id"`
+	BigQueryProjectID        string `json:"bigquery_project_id"`
+	BigQueryDatasetID        string `json:"bigquery_dataset_id"`
+	BigQueryTableID          string `json:"bigquery_table_id"`
+	AuthenticationType       string `json:"authentication_type"`
+	ServiceAccountEmail      string `json:"service_account_email"`
+	WorkloadIdentityProvider string `json:"workload_identity_provider"`
+	Status                   string `json:"status"`
+	StatusMessage            string `json:"status_message"`
+	CreatedAt                string `json:"created_at"`
+	UpdatedAt                string `json:"updated_at"`
+	IntegratedAt             string `json:"integrated_at"`
+}
+
+type NewGCPProject struct {
+	BillingAccountID string `json:"billing_account_id"`
+	UpdateGCPProject
+}
+
+type UpdateGCPProject struct {
+	Name                     string `json:"name"`
+	BigQueryProjectID        string `json:"bigquery_project_id"`
+	BigQueryDatasetID        string `json:"bigquery_dataset_id"`
+	ServiceAccountKey        string `json:"service_account_key,omitempty"`
+	ServiceAccountEmail      string `json:"service_account_email,omitempty"`
+	WorkloadIdentityProvider string `json:"workload_identity_provider,omitempty"`
+}
+
+type BulkUpdateProject struct {
+	ID int64 `json:"id"`
+	UpdateProject
+}
+
+type BulkProjectResult struct {
+	Project
+	Error string `json:"error,omitempty"`
+}
+
 type Integration struct {
 	RoleArn            string             `json:"role_arn"`
 	BucketName         string             `json:"bucket_name"`
 	AccountNumber      string             `json:"account_number"`
 	ExternalID         string             `json:"external_id"`
 	RequestType        string             `json:"RequestType"`
+	ProjectID          int64              `json:"project_id,omitempty"`
 	ResourceProperties ResourceProperties `json:"ResourceProperties"`
 }
 
@@ -41,3 +89,149 @@ type ResourceProperties struct {
 type IntegrationResponse struct {
 	Status string `json:"status"`
 }
+
+type CURConfiguration struct {
+	BucketName     string `json:"bucket_name"`
+	Prefix         string `json:"prefix"`
+	ReportName     string `json:"report_name"`
+	Format         string `json:"format"`
+	Status         string `json:"status,omitempty"`
+	StatusMessage  string `json:"status_message,omitempty"`
+	LastIngestedAt string `json:"last_ingested_at,omitempty"`
+	UpdatedAt      string `json:"updated_at,omitempty"`
+}
+
+type SystemBucket struct {
+	BucketName    string `json:"bucket_name"`
+	Status        string `json:"status,omitempty"`
+	StatusMessage string `json:"status_message,omitempty"`
+	VerifiedAt    string `json:"verified_at,omitempty"`
+}
+
+type AzureProject struct {
+	ID               int    `json:"id"`
+	Client           int    `json:"client"`
+	Name             string `json:"name"`
+	TenantID         string `json:"tenant_id"`
+	SubscriptionID   string `json:"subscription_id"`
+	BillingAccountID string `json:"billing_account_id"`
+	CreatedAt        string `json:"created_at"`
+	UpdatedAt        string `json:"updated_at"`
+	IntegratedAt     string `json:"integrated_at"`
+}
+
+type NewAzureProject struct {
+	Name             string `json:"name"`
+	TenantID         string `json:"tenant_id"`
+	SubscriptionID   string `json:"subscription_id,omitempty"`
+	BillingAccountID string `json:"billing_account_id,omitempty"`
+}
+
+type UpdateAzureProject struct {
+	Name string `json:"name"`
+}
+
+type AzureIntegration struct {
+	ApplicationID      string `json:"application_id"`
+	ClientSecret       string `json:"client_secret,omitempty"`
+	StorageAccountID   string `json:"storage_account_id"`
+	StorageContainer   string `json:"storage_container"`
+	ExportDirectory    string `json:"export_directory"`
+	AuthenticationType string `json:"authentication_type,omitempty"`
+	FederatedIssuer    string `json:"federated_issuer,omitempty"`
+	FederatedSubject   string `json:"federated_subject,omitempty"`
+	Status             string `json:"status,omitempty"`
+	StatusMessage      string `json:"status_message,omitempty"`
+	UpdatedAt          string `json:"updated_at,omitempty"`
+}
+
+type EKSCluster struct {
+	ID              int    `json:"id"`
+	ClusterName     string `json:"cluster_name"`
+	Region          string `json:"region"`
+	AccountNumber   string `json:"account_number"`
+	AgentToken      string `json:"agent_token"`
+	HelmValues      string `json:"helm_values"`
+	AgentStatus     string `json:"agent_status"`
+	AgentVersion    string `json:"agent_version"`
+	LastHeartbeatAt string `json:"last_heartbeat_at"`
+	CreatedAt       string `json:"created_at"`
+	UpdatedAt       string `json:"updated_at"`
+}
+
+type NewEKSCluster struct {
+	ClusterName   string `json:"cluster_name"`
+	Region        string `json:"region"`
+	AccountNumber string `json:"account_number"`
+}
+
+type ComputeCopilot struct {
+	Enabled              bool                      `json:"enabled"`
+	AutoScalingGroups    []string                  `json:"auto_scaling_groups"`
+	EKSNodeGroups        []ComputeCopilotNodeGroup `json:"eks_node_groups"`
+	SpotPercentage       int64                     `json:"spot_percentage"`
+	OnDemandBaseCapacity int64                     `json:"on_demand_base_capacity"`
+	Status               string                    `json:"status,omitempty"`
+	StatusMessage        string                    `json:"status_message,omitempty"`
+	UpdatedAt            string                    `json:"updated_at,omitempty"`
+}
+
+type ComputeCopilotNodeGroup struct {
+	ClusterName   string `json:"cluster_name"`
+	NodeGroupName string `json:"node_group_name"`
+}
+
+type CommitmentManagement struct {
+	Enabled          bool     `json:"enabled"`
+	RiskTolerance    string   `json:"risk_tolerance"`
+	TermLengths      []int64  `json:"term_lengths"`
+	PaymentOptions   []string `json:"payment_options"`
+	ExcludedServices []string `json:"excluded_services"`
+	UpdatedAt        string   `json:"updated_at,omitempty"`
+}
+
+type WAFRWorkload struct {
+	ID              int      `json:"id"`
+	Project         int      `json:"project"`
+	Name            string   `json:"name"`
+	Environment     string   `json:"environment"`
+	Regions         []string `json:"regions"`
+	Lenses          []string `json:"lenses"`
+	Owners          []string `json:"owners"`
+	WorkloadArn     string   `json:"workload_arn"`
+	HighRiskCount   int64    `json:"high_risk_count"`
+	MediumRiskCount int64    `json:"medium_risk_count"`
+	LastReviewedAt  string   `json:"last_reviewed_at"`
+	CreatedAt       string   `json:"created_at"`
+	UpdatedAt       string   `json:"updated_at"`
+}
+
+type NewWAFRWorkload struct {
+	Name        string   `json:"name"`
+	Environment string   `json:"environment"`
+	Regions     []string `json:"regions"`
+	Lenses      []string `json:"lenses"`
+	Owners      []string `json:"owners"`
+}
+
+type CostAllocationTags struct {
+	TagKeys   []string            `json:"tag_keys"`
+	Tags      []CostAllocationTag `json:"tags,omitempty"`
+	UpdatedAt string              `json:"updated_at,omitempty"`
+}
+
+type CostAllocationTag struct {
+	Key           string `json:"key"`
+	Status        string `json:"status"`
+	StatusMessage string `json:"status_message,omitempty"`
+}
+
+type APIToken struct {
+	ID        int    `json:"id"`
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+type NewAPIToken struct {
+	ExpiresIn int64 `json:"expires_in"`
+}
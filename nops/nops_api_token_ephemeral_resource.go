@@ -0,0 +1,172 @@
+package nops
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ ephemeral.EphemeralResource                   = &apiTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure      = &apiTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithValidateConfig = &apiTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose          = &apiTokenEphemeralResource{}
+)
+
+// Lifetime bounds of the API tokens, in seconds.
+const (
+	apiTokenDefaultExpiresIn int64 = 3600
+	apiTokenMinExpiresIn     int64 = 300
+	apiTokenMaxExpiresIn     int64 = 43200
+)
+
+// apiTokenPrivateKey is the private data key holding the identifier of the token, to revoke it on close.
+const apiTokenPrivateKey = "token_id"
+
+// apiTokenEphemeralResource is the ephemeral resource implementation.
+type apiTokenEphemeralResource struct {
+	client *Client
+}
+
+type apiTokenEphemeralResourceModel struct {
+	ExpiresIn types.Int64  `tfsdk:"expires_in"`
+	Token     types.String `tfsdk:"token"`
+	ExpiresAt types.String `tfsdk:"expires_at"`
+}
+
+// NewAPITokenEphemeralResource is a helper function to simplify the provider implementation.
+func NewAPITokenEphemeralResource() ephemeral.EphemeralResource {
+	return &apiTokenEphemeralResource{}
+}
+
+// Configure adds the provider configured client to the ephemeral resource.
+func (r *apiTokenEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the ephemeral resource type name.
+func (r *apiTokenEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_api_token"
+}
+
+// Schema defines the schema for the ephemeral resource.
+func (r *apiTokenEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Exchanges the API key of the provider for a short-lived nOps API token, revoked once Terraform no longer needs it. " +
+			"The token is never stored in the plan or state, requires Terraform 1.10 or later.",
+		Attributes: map[string]schema.Attribute{
+			"expires_in": schema.Int64Attribute{
+				Optional: true,
+				Description: fmt.Sprintf("Lifetime of the token in seconds, between %d and %d, defaults to %d",
+					apiTokenMinExpiresIn, apiTokenMaxExpiresIn, apiTokenDefaultExpiresIn),
+			},
+			"token": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "nOps API token, sent in the `X-Nops-Api-Key` header",
+			},
+			"expires_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the token expires, as reported by nOps",
+			},
+		},
+	}
+}
+
+// ValidateConfig validates the lifetime of the token.
+func (r *apiTokenEphemeralResource) ValidateConfig(ctx context.Context, req ephemeral.ValidateConfigRequest, resp *ephemeral.ValidateConfigResponse) {
+	var config apiTokenEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if expiresIn := config.ExpiresIn; !expiresIn.IsNull() && !expiresIn.IsUnknown() &&
+		(expiresIn.ValueInt64() < apiTokenMinExpiresIn || expiresIn.ValueInt64() > apiTokenMaxExpiresIn) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("expires_in"),
+			"Invalid token lifetime",
+			fmt.Sprintf("The token lifetime must be between %d and %d seconds, got %d", apiTokenMinExpiresIn, apiTokenMaxExpiresIn, expiresIn.ValueInt64()),
+		)
+	}
+}
+
+// Open creates the token.
+func (r *apiTokenEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data apiTokenEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	expiresIn := apiTokenDefaultExpiresIn
+	if !data.ExpiresIn.IsNull() {
+		expiresIn = data.ExpiresIn.ValueInt64()
+	}
+
+	token, err := r.client.CreateAPIToken(ctx, NewAPIToken{ExpiresIn: expiresIn})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating nOps API token",
+			"Could not create API token, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, apiTokenPrivateKey, []byte(strconv.Itoa(token.ID)))...)
+
+	data.Token = types.StringValue(token.Token)
+	data.ExpiresAt = serverTimestamp(token.ExpiresAt)
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+// Close revokes the token.
+func (r *apiTokenEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	value, diags := req.Private.GetKey(ctx, apiTokenPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || value == nil {
+		return
+	}
+
+	id, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error revoking nOps API token",
+			"Could not parse the token identifier, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Expired tokens are already gone.
+	err = r.client.DeleteAPIToken(ctx, id)
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error revoking nOps API token",
+			"Could not revoke API token, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	tflog.Debug(ctx, "Revoked nOps API token", map[string]any{"id": id})
+}
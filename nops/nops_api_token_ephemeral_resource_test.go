@@ -0,0 +1,86 @@
+package nops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestAPITokenEphemeralResourceLifecycle(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops()
+	server := newTestProviderServer(t, fake).(tfprotov6.EphemeralResourceServer)
+
+	var schemaResp ephemeral.SchemaResponse
+	NewAPITokenEphemeralResource().Schema(ctx, ephemeral.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	config := testDynamicValue(t, objectType, map[string]tftypes.Value{
+		"expires_in": tftypes.NewValue(tftypes.Number, 900),
+		"token":      tftypes.NewValue(tftypes.String, nil),
+		"expires_at": tftypes.NewValue(tftypes.String, nil),
+	})
+
+	openResp, err := server.OpenEphemeralResource(ctx, &tfprotov6.OpenEphemeralResourceRequest{TypeName: "nops_api_token", Config: &config})
+	if err != nil || len(openResp.Diagnostics) > 0 {
+		t.Fatalf("unexpected error opening API token: %v %v", err, openResp.Diagnostics)
+	}
+	result, err := openResp.Result.Unmarshal(objectType)
+	if err != nil {
+		t.Fatalf("unexpected error decoding API token: %s", err)
+	}
+	values := map[string]tftypes.Value{}
+	_ = result.As(&values)
+	var token, expiresAt string
+	_ = values["token"].As(&token)
+	_ = values["expires_at"].As(&expiresAt)
+	if token != "token-1-900" || expiresAt != "2024-10-21T15:04:05Z" {
+		t.Errorf("expected a token valid for 900 seconds, got %q expiring at %q", token, expiresAt)
+	}
+	if len(fake.apiTokens) != 1 {
+		t.Fatalf("expected the token to be created in nOps")
+	}
+
+	closeResp, err := server.CloseEphemeralResource(ctx, &tfprotov6.CloseEphemeralResourceRequest{TypeName: "nops_api_token", Private: openResp.Private})
+	if err != nil || len(closeResp.Diagnostics) > 0 {
+		t.Fatalf("unexpected error closing API token: %v %v", err, closeResp.Diagnostics)
+	}
+	if len(fake.apiTokens) != 0 || !fake.requested("DELETE", "/c/admin/api_tokens/1/") {
+		t.Errorf("expected the token to be revoked in nOps")
+	}
+}
+
+func TestAPITokenEphemeralResourceValidateConfig(t *testing.T) {
+	ctx := context.Background()
+	server := newTestProviderServer(t, newFakeNops()).(tfprotov6.EphemeralResourceServer)
+
+	var schemaResp ephemeral.SchemaResponse
+	NewAPITokenEphemeralResource().Schema(ctx, ephemeral.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	cases := map[string]struct {
+		expiresIn      any
+		expectedErrors int
+	}{
+		"default":   {expiresIn: nil},
+		"valid":     {expiresIn: 900},
+		"too short": {expiresIn: 60, expectedErrors: 1},
+		"too long":  {expiresIn: 86400, expectedErrors: 1},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			config := testDynamicValue(t, objectType, map[string]tftypes.Value{
+				"expires_in": tftypes.NewValue(tftypes.Number, c.expiresIn),
+				"token":      tftypes.NewValue(tftypes.String, nil),
+				"expires_at": tftypes.NewValue(tftypes.String, nil),
+			})
+			resp, err := server.ValidateEphemeralResourceConfig(ctx, &tfprotov6.ValidateEphemeralResourceConfigRequest{TypeName: "nops_api_token", Config: &config})
+			if err != nil || len(resp.Diagnostics) != c.expectedErrors {
+				t.Errorf("expected %d errors, got %v %v", c.expectedErrors, err, resp.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,397 @@
+package nops
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &azureIntegrationResource{}
+	_ resource.ResourceWithConfigure      = &azureIntegrationResource{}
+	_ resource.ResourceWithImportState    = &azureIntegrationResource{}
+	_ resource.ResourceWithModifyPlan     = &azureIntegrationResource{}
+	_ resource.ResourceWithValidateConfig = &azureIntegrationResource{}
+)
+
+// Authentication types of the Azure integrations.
+const (
+	azureAuthenticationClientSecret        = "client_secret"
+	azureAuthenticationFederatedCredential = "federated_credential"
+)
+
+// azureIntegrationStatusFailed is the status reported by nOps when it can't access the scope or the cost exports.
+const azureIntegrationStatusFailed = "failed"
+
+var (
+	// storageAccountIDPattern matches the Azure resource ID of a storage account.
+	storageAccountIDPattern = regexp.MustCompile(`^/subscriptions/[0-9a-fA-F-]{36}/resourceGroups/[^/]+/providers/Microsoft\.Storage/storageAccounts/[a-z0-9]{3,24}$`)
+	// storageContainerPattern matches the Azure blob container naming rules, except for consecutive hyphens.
+	storageContainerPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,61}[a-z0-9]$`)
+)
+
+// azureIntegrationResource is the resource implementation.
+type azureIntegrationResource struct {
+	client *Client
+}
+
+type azureIntegrationModel struct {
+	ID                 types.Int64  `tfsdk:"id"`
+	LastUpdated        types.String `tfsdk:"last_updated"`
+	ProjectID          types.Int64  `tfsdk:"project_id"`
+	ApplicationID      types.String `tfsdk:"application_id"`
+	ClientSecret       types.String `tfsdk:"client_secret"`
+	StorageAccountID   types.String `tfsdk:"storage_account_id"`
+	StorageContainer   types.String `tfsdk:"storage_container"`
+	ExportDirectory    types.String `tfsdk:"export_directory"`
+	AuthenticationType types.String `tfsdk:"authentication_type"`
+	FederatedIssuer    types.String `tfsdk:"federated_issuer"`
+	FederatedSubject   types.String `tfsdk:"federated_subject"`
+	Status             types.String `tfsdk:"status"`
+	StatusMessage      types.String `tfsdk:"status_message"`
+}
+
+// NewAzureIntegrationResource is a helper function to simplify the provider implementation.
+func NewAzureIntegrationResource() resource.Resource {
+	return &azureIntegrationResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *azureIntegrationResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *azureIntegrationResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_azure_integration"
+}
+
+// Schema defines the schema for the resource.
+func (r *azureIntegrationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Configures how nOps accesses an Azure project: the service principal it authenticates as, with a client secret " +
+			"or a federated credential issued by nOps, and the storage account the cost exports are delivered to.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Identifier of the integration, the nOps project identifier",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the resource was last updated, as reported by nOps",
+			},
+			"project_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "nOps project identifier of the Azure project, usually `nops_azure_project.id`",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"application_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Application (client) ID of the Microsoft Entra ID service principal nOps authenticates as. It needs the Cost Management Reader role on the project scope and read access to the cost exports",
+			},
+			"client_secret": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Client secret of the service principal. When it isn't set nOps authenticates with a federated credential, which must be added to the application with `federated_issuer` and `federated_subject`",
+			},
+			"storage_account_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Azure resource ID of the storage account the Cost Management exports are delivered to",
+			},
+			"storage_container": schema.StringAttribute{
+				Required:    true,
+				Description: "Blob container of the storage account the exports are delivered to",
+			},
+			"export_directory": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+				Description: "Directory of the exports in the container, without leading or trailing slashes. Defaults to the container root",
+			},
+			"authentication_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "How nOps authenticates as the service principal, `client_secret` or `federated_credential`",
+			},
+			"federated_issuer": schema.StringAttribute{
+				Computed:    true,
+				Description: "Issuer of the federated credential nOps authenticates with, empty when authenticating with a client secret",
+			},
+			"federated_subject": schema.StringAttribute{
+				Computed:    true,
+				Description: "Subject of the federated credential nOps authenticates with, empty when authenticating with a client secret. The credential audience is `api://AzureADTokenExchange`",
+			},
+			"status": schema.StringAttribute{
+				Computed:    true,
+				Description: "Status of the integration reported by nOps, such as `pending`, `active` or `failed`",
+			},
+			"status_message": schema.StringAttribute{
+				Computed:    true,
+				Description: "Details about the integration status, explaining the failure when `status` is `failed`",
+			},
+		},
+	}
+}
+
+// ValidateConfig validates the identifiers and storage locations follow the Azure formats.
+func (r *azureIntegrationResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config azureIntegrationModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.ApplicationID.IsNull() && !config.ApplicationID.IsUnknown() && !azureGUIDPattern.MatchString(config.ApplicationID.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("application_id"),
+			"Invalid Azure identifier",
+			fmt.Sprintf("%q is not a valid application_id, expected a GUID such as 00000000-0000-0000-0000-000000000000", config.ApplicationID.ValueString()),
+		)
+	}
+
+	if !config.ClientSecret.IsNull() && !config.ClientSecret.IsUnknown() && config.ClientSecret.ValueString() == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_secret"),
+			"Invalid client secret",
+			"client_secret must not be empty, remove it to authenticate with a federated credential.",
+		)
+	}
+
+	if !config.StorageAccountID.IsNull() && !config.StorageAccountID.IsUnknown() && !storageAccountIDPattern.MatchString(config.StorageAccountID.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("storage_account_id"),
+			"Invalid storage account ID",
+			fmt.Sprintf("%q is not a valid storage account ID, expected /subscriptions/<subscription_id>/resourceGroups/<resource_group>/providers/Microsoft.Storage/storageAccounts/<name>", config.StorageAccountID.ValueString()),
+		)
+	}
+
+	if container := config.StorageContainer; !container.IsNull() && !container.IsUnknown() &&
+		(!storageContainerPattern.MatchString(container.ValueString()) || strings.Contains(container.ValueString(), "--")) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("storage_container"),
+			"Invalid storage container",
+			fmt.Sprintf("%q is not a valid container name, it must be 3 to 63 lowercase letters, numbers or single hyphens", container.ValueString()),
+		)
+	}
+
+	if directory := config.ExportDirectory.ValueString(); strings.HasPrefix(directory, "/") || strings.HasSuffix(directory, "/") {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("export_directory"),
+			"Invalid export directory",
+			fmt.Sprintf("%q must not start or end with a slash", directory),
+		)
+	}
+}
+
+// ModifyPlan plans the authentication type from the client secret and keeps the federated credential while it
+// doesn't change, so the credential added to the application isn't replaced on every update.
+func (r *azureIntegrationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan azureIntegrationModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.ClientSecret.IsUnknown() {
+		return
+	}
+
+	plan.AuthenticationType = types.StringValue(azureAuthenticationFederatedCredential)
+	if !plan.ClientSecret.IsNull() {
+		plan.AuthenticationType = types.StringValue(azureAuthenticationClientSecret)
+		plan.FederatedIssuer = types.StringValue("")
+		plan.FederatedSubject = types.StringValue("")
+	} else if !req.State.Raw.IsNull() {
+		var state azureIntegrationModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if state.AuthenticationType.Equal(plan.AuthenticationType) && state.ProjectID.Equal(plan.ProjectID) {
+			plan.FederatedIssuer = state.FederatedIssuer
+			plan.FederatedSubject = state.FederatedSubject
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *azureIntegrationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan azureIntegrationModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	integration, err := r.client.PutAzureIntegration(ctx, plan.ProjectID.ValueInt64(), plan.integration())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error configuring Azure integration",
+			fmt.Sprintf("nOps rejected the integration of Azure project %d: %s", plan.ProjectID.ValueInt64(), err.Error()),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("Azure integration configured for project %d with status %s", plan.ProjectID.ValueInt64(), integration.Status))
+	plan.ID = plan.ProjectID
+	plan.refresh(integration)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *azureIntegrationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state azureIntegrationModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	integration, err := r.client.GetAzureIntegration(ctx, state.ID.ValueInt64())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, fmt.Sprintf("No Azure integration found in nOps for project %d, removing it from state", state.ID.ValueInt64()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote Azure integration",
+			err.Error(),
+		)
+		return
+	}
+
+	// nOps never returns the client secret, the configured one is kept.
+	state.ProjectID = state.ID
+	state.ApplicationID = types.StringValue(integration.ApplicationID)
+	state.StorageAccountID = types.StringValue(integration.StorageAccountID)
+	state.StorageContainer = types.StringValue(integration.StorageContainer)
+	state.ExportDirectory = types.StringValue(integration.ExportDirectory)
+	state.refresh(integration)
+	if integration.Status == azureIntegrationStatusFailed {
+		resp.Diagnostics.AddWarning(
+			fmt.Sprintf("nOps can't access Azure project %d", state.ID.ValueInt64()),
+			integration.StatusMessage,
+		)
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *azureIntegrationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The integration is imported with the identifier of its project, the client secret can't be imported.
+	val, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Error parsing ID for import, please check for a correct project ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), val)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *azureIntegrationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan azureIntegrationModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	integration, err := r.client.PutAzureIntegration(ctx, plan.ProjectID.ValueInt64(), plan.integration())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating Azure integration",
+			fmt.Sprintf("nOps rejected the integration of Azure project %d: %s", plan.ProjectID.ValueInt64(), err.Error()),
+		)
+		return
+	}
+
+	plan.ID = plan.ProjectID
+	plan.refresh(integration)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *azureIntegrationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state azureIntegrationModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteAzureIntegration(ctx, state.ID.ValueInt64())
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error deleting Azure integration",
+			err.Error(),
+		)
+		return
+	}
+}
+
+// integration returns the nOps payload for the planned integration.
+func (m *azureIntegrationModel) integration() AzureIntegration {
+	return AzureIntegration{
+		ApplicationID:    m.ApplicationID.ValueString(),
+		ClientSecret:     m.ClientSecret.ValueString(),
+		StorageAccountID: m.StorageAccountID.ValueString(),
+		StorageContainer: m.StorageContainer.ValueString(),
+		ExportDirectory:  m.ExportDirectory.ValueString(),
+	}
+}
+
+// refresh sets the attributes computed by nOps.
+func (m *azureIntegrationModel) refresh(integration *AzureIntegration) {
+	m.AuthenticationType = types.StringValue(integration.AuthenticationType)
+	m.FederatedIssuer = types.StringValue(integration.FederatedIssuer)
+	m.FederatedSubject = types.StringValue(integration.FederatedSubject)
+	m.Status = types.StringValue(integration.Status)
+	m.StatusMessage = types.StringValue(integration.StatusMessage)
+	if integration.UpdatedAt != "" {
+		m.LastUpdated = serverTimestamp(integration.UpdatedAt)
+	} else if m.LastUpdated.IsNull() || m.LastUpdated.IsUnknown() {
+		m.LastUpdated = types.StringValue(time.Now().Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,177 @@
+package nops
+
+import (
+	"context"
+	"testing"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// newAzureIntegrationTestFake returns fake nOps APIs with the Azure project 1.
+func newAzureIntegrationTestFake() *fakeNops {
+	fake := newFakeNops()
+	fake.azureProjects[1] = AzureProject{ID: 1, Client: 1000, Name: "azure-production", TenantID: testAzureTenantID, SubscriptionID: testAzureSubscriptionID}
+
+	return fake
+}
+
+func testAzureIntegrationModel() azureIntegrationModel {
+	return azureIntegrationModel{
+		ID:                 types.Int64Unknown(),
+		LastUpdated:        types.StringUnknown(),
+		ProjectID:          types.Int64Value(1),
+		ApplicationID:      types.StringValue("5e6f7a8b-9c0d-4e1f-a2b3-c4d5e6f7a8b9"),
+		ClientSecret:       types.StringNull(),
+		StorageAccountID:   types.StringValue("/subscriptions/" + testAzureSubscriptionID + "/resourceGroups/finops/providers/Microsoft.Storage/storageAccounts/nopsexports"),
+		StorageContainer:   types.StringValue("cost-exports"),
+		ExportDirectory:    types.StringValue("nops"),
+		AuthenticationType: types.StringUnknown(),
+		FederatedIssuer:    types.StringUnknown(),
+		FederatedSubject:   types.StringUnknown(),
+		Status:             types.StringUnknown(),
+		StatusMessage:      types.StringUnknown(),
+	}
+}
+
+func TestAzureIntegrationResourceValidateConfig(t *testing.T) {
+	cases := map[string]struct {
+		update         func(*azureIntegrationModel)
+		expectedErrors int
+	}{
+		"federated credential": {update: func(m *azureIntegrationModel) {}},
+		"client secret":        {update: func(m *azureIntegrationModel) { m.ClientSecret = types.StringValue("secret") }},
+		"empty client secret":  {update: func(m *azureIntegrationModel) { m.ClientSecret = types.StringValue("") }, expectedErrors: 1},
+		"invalid application":  {update: func(m *azureIntegrationModel) { m.ApplicationID = types.StringValue("nops") }, expectedErrors: 1},
+		"invalid storage account": {update: func(m *azureIntegrationModel) {
+			m.StorageAccountID = types.StringValue("nopsexports")
+		}, expectedErrors: 1},
+		"invalid container":   {update: func(m *azureIntegrationModel) { m.StorageContainer = types.StringValue("Cost_Exports") }, expectedErrors: 1},
+		"consecutive hyphens": {update: func(m *azureIntegrationModel) { m.StorageContainer = types.StringValue("cost--exports") }, expectedErrors: 1},
+		"slash directory":     {update: func(m *azureIntegrationModel) { m.ExportDirectory = types.StringValue("/nops") }, expectedErrors: 1},
+		"unknown storage data": {update: func(m *azureIntegrationModel) {
+			m.StorageAccountID, m.StorageContainer = types.StringUnknown(), types.StringUnknown()
+		}},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, s := newTestResource(t, newFakeNops(), &azureIntegrationResource{})
+			config := testAzureIntegrationModel()
+			c.update(&config)
+
+			var resp fwresource.ValidateConfigResponse
+			r.ValidateConfig(context.Background(), fwresource.ValidateConfigRequest{Config: tfsdk.Config(testResourceState(t, s, &config))}, &resp)
+			if resp.Diagnostics.ErrorsCount() != c.expectedErrors {
+				t.Errorf("expected %d errors, got %v", c.expectedErrors, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestAzureIntegrationResourceModifyPlan(t *testing.T) {
+	ctx := context.Background()
+	r, s := newTestResource(t, newAzureIntegrationTestFake(), &azureIntegrationResource{})
+
+	state := testAzureIntegrationModel()
+	state.ID = types.Int64Value(1)
+	state.AuthenticationType = types.StringValue(azureAuthenticationFederatedCredential)
+	state.FederatedIssuer = types.StringValue("https://app.nops.io")
+	state.FederatedSubject = types.StringValue("nops:client:1000:project:1")
+
+	// Updating the storage location keeps the federated credential added to the application.
+	plan := testAzureIntegrationModel()
+	plan.ID = types.Int64Value(1)
+	plan.StorageContainer = types.StringValue("exports")
+	resp := fwresource.ModifyPlanResponse{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}
+	r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{Plan: resp.Plan, State: testResourceState(t, s, &state)}, &resp)
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error modifying plan: %v", resp.Diagnostics)
+	}
+	if !plan.FederatedSubject.Equal(state.FederatedSubject) || plan.AuthenticationType.ValueString() != azureAuthenticationFederatedCredential {
+		t.Errorf("expected the federated credential to be kept, got %s with %s", plan.AuthenticationType, plan.FederatedSubject)
+	}
+
+	// Switching to a client secret drops the federated credential.
+	plan = testAzureIntegrationModel()
+	plan.ClientSecret = types.StringValue("secret")
+	resp = fwresource.ModifyPlanResponse{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}
+	r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{Plan: resp.Plan, State: testResourceState(t, s, &state)}, &resp)
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if plan.AuthenticationType.ValueString() != azureAuthenticationClientSecret || plan.FederatedSubject.ValueString() != "" {
+		t.Errorf("expected client secret authentication, got %s with %s", plan.AuthenticationType, plan.FederatedSubject)
+	}
+}
+
+func TestAzureIntegrationResourceCreate(t *testing.T) {
+	ctx := context.Background()
+	fake := newAzureIntegrationTestFake()
+	r, s := newTestResource(t, fake, &azureIntegrationResource{})
+
+	plan := testAzureIntegrationModel()
+	resp := fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error creating Azure integration: %v", resp.Diagnostics)
+	}
+
+	var state azureIntegrationModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if state.ID.ValueInt64() != 1 || state.AuthenticationType.ValueString() != azureAuthenticationFederatedCredential {
+		t.Errorf("expected a federated credential integration of project 1, got %s with %s", state.ID, state.AuthenticationType)
+	}
+	if state.FederatedIssuer.ValueString() != "https://app.nops.io" || state.FederatedSubject.ValueString() != "nops:client:1000:project:1" {
+		t.Errorf("expected the federated credential issued by nOps, got %s and %s", state.FederatedIssuer, state.FederatedSubject)
+	}
+	if !fake.requested("PUT", "/c/admin/projectazure/1/integration/") {
+		t.Errorf("expected the integration to be sent to nOps")
+	}
+}
+
+func TestAzureIntegrationResourceCreateUnknownProject(t *testing.T) {
+	r, s := newTestResource(t, newFakeNops(), &azureIntegrationResource{})
+
+	plan := testAzureIntegrationModel()
+	resp := fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(context.Background(), fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("expected an error integrating an Azure project that doesn't exist")
+	}
+}
+
+func TestAzureIntegrationResourceReadFailed(t *testing.T) {
+	ctx := context.Background()
+	fake := newAzureIntegrationTestFake()
+	fake.azureIntegrations[1] = AzureIntegration{
+		ApplicationID:      "5e6f7a8b-9c0d-4e1f-a2b3-c4d5e6f7a8b9",
+		StorageAccountID:   "/subscriptions/" + testAzureSubscriptionID + "/resourceGroups/finops/providers/Microsoft.Storage/storageAccounts/nopsexports",
+		StorageContainer:   "missing",
+		AuthenticationType: azureAuthenticationClientSecret,
+		Status:             azureIntegrationStatusFailed,
+		StatusMessage:      "The container missing wasn't found",
+	}
+	r, s := newTestResource(t, fake, &azureIntegrationResource{})
+
+	model := testAzureIntegrationModel()
+	model.ID = types.Int64Value(1)
+	model.ClientSecret = types.StringValue("secret")
+	resp := fwresource.ReadResponse{State: testResourceState(t, s, &model)}
+	r.Read(ctx, fwresource.ReadRequest{State: resp.State}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error reading Azure integration: %v", resp.Diagnostics)
+	}
+
+	var state azureIntegrationModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if state.StorageContainer.ValueString() != "missing" || state.ExportDirectory.ValueString() != "" || state.Status.ValueString() != azureIntegrationStatusFailed {
+		t.Errorf("expected the integration to be refreshed, got %s/%s with status %s", state.StorageContainer, state.ExportDirectory, state.Status)
+	}
+	if state.ClientSecret.ValueString() != "secret" {
+		t.Errorf("expected the client secret to be kept, got %s", state.ClientSecret)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("expected a warning about the failed integration, got %v", resp.Diagnostics)
+	}
+}
@@ -0,0 +1,343 @@
+package nops
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &azureProjectResource{}
+	_ resource.ResourceWithConfigure      = &azureProjectResource{}
+	_ resource.ResourceWithImportState    = &azureProjectResource{}
+	_ resource.ResourceWithValidateConfig = &azureProjectResource{}
+)
+
+// azureGUIDPattern matches the GUIDs used by Microsoft Entra ID and Azure for tenants, subscriptions and applications.
+var azureGUIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// azureProjectResource is the resource implementation.
+type azureProjectResource struct {
+	client *Client
+}
+
+type azureProjectModel struct {
+	ID               types.Int64  `tfsdk:"id"`
+	LastUpdated      types.String `tfsdk:"last_updated"`
+	Name             types.String `tfsdk:"name"`
+	TenantID         types.String `tfsdk:"tenant_id"`
+	SubscriptionID   types.String `tfsdk:"subscription_id"`
+	BillingAccountID types.String `tfsdk:"billing_account_id"`
+	Scope            types.String `tfsdk:"scope"`
+	ClientID         types.Int64  `tfsdk:"client_id"`
+	CreatedAt        types.String `tfsdk:"created_at"`
+	UpdatedAt        types.String `tfsdk:"updated_at"`
+	IntegratedAt     types.String `tfsdk:"integrated_at"`
+}
+
+// NewAzureProjectResource is a helper function to simplify the provider implementation.
+func NewAzureProjectResource() resource.Resource {
+	return &azureProjectResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *azureProjectResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *azureProjectResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_azure_project"
+}
+
+// Schema defines the schema for the resource.
+func (r *azureProjectResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Onboards an Azure subscription or billing account to the nOps platform. " +
+			"nOps starts collecting its costs once the access is configured with `nops_azure_integration`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "nOps project identifier",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the resource was last updated, as reported by nOps",
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "nOps project name",
+			},
+			"tenant_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Microsoft Entra ID tenant of the subscription or billing account",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"subscription_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Azure subscription to onboard, conflicts with `billing_account_id`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"billing_account_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Azure billing account to onboard with every subscription billed to it, conflicts with `subscription_id`. Enterprise Agreement enrollment numbers and Microsoft Customer Agreement billing account identifiers are supported",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"scope": schema.StringAttribute{
+				Computed:    true,
+				Description: "Azure scope nOps reads the costs of, `/subscriptions/<subscription_id>` or `/providers/Microsoft.Billing/billingAccounts/<billing_account_id>`. Role assignments of the integration principal use this scope",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"client_id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "nOps client identifier, the nOps organization owning the project",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"created_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the project was created in nOps",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"updated_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the project was last updated in nOps, including changes made outside of Terraform",
+			},
+			"integrated_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the integration with nOps was completed, empty while the integration is pending",
+			},
+		},
+	}
+}
+
+// ValidateConfig validates the identifiers follow the Azure formats and a single scope is onboarded.
+func (r *azureProjectResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config azureProjectModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for attribute, value := range map[string]types.String{"tenant_id": config.TenantID, "subscription_id": config.SubscriptionID} {
+		if !value.IsNull() && !value.IsUnknown() && !azureGUIDPattern.MatchString(value.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attribute),
+				"Invalid Azure identifier",
+				fmt.Sprintf("%q is not a valid %s, expected a GUID such as 00000000-0000-0000-0000-000000000000", value.ValueString(), attribute),
+			)
+		}
+	}
+
+	if config.SubscriptionID.IsUnknown() || config.BillingAccountID.IsUnknown() {
+		return
+	}
+	if config.SubscriptionID.IsNull() == config.BillingAccountID.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("subscription_id"),
+			"Invalid Azure scope",
+			"Exactly one of subscription_id or billing_account_id must be set, use one nops_azure_project per subscription or billing account.",
+		)
+	}
+	if !config.BillingAccountID.IsNull() && config.BillingAccountID.ValueString() == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("billing_account_id"),
+			"Invalid Azure billing account",
+			"billing_account_id must not be empty.",
+		)
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *azureProjectResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan azureProjectModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, err := r.client.CreateAzureProject(ctx, NewAzureProject{
+		Name:             plan.Name.ValueString(),
+		TenantID:         plan.TenantID.ValueString(),
+		SubscriptionID:   plan.SubscriptionID.ValueString(),
+		BillingAccountID: plan.BillingAccountID.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating Azure project",
+			"Could not create Azure project, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("Azure project %d created for scope %s", project.ID, azureScope(project)))
+	plan.refresh(project)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *azureProjectResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state azureProjectModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, err := r.client.GetAzureProject(ctx, state.ID.ValueInt64())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, fmt.Sprintf("Azure project %d wasn't found in nOps, removing it from state", state.ID.ValueInt64()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote Azure project data",
+			err.Error(),
+		)
+		return
+	}
+
+	state.Name = types.StringValue(project.Name)
+	state.TenantID = types.StringValue(project.TenantID)
+	state.SubscriptionID = azureOptionalID(project.SubscriptionID)
+	state.BillingAccountID = azureOptionalID(project.BillingAccountID)
+	state.refresh(project)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *azureProjectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Capability to import existing Azure projects into the TF state without recreation.
+	val, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Error parsing ID for import, please check for a correct project ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), val)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *azureProjectResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan azureProjectModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only the name can be updated, changing the tenant or the scope replaces the project.
+	project, err := r.client.UpdateAzureProject(ctx, plan.ID.ValueInt64(), UpdateAzureProject{Name: plan.Name.ValueString()})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating Azure project",
+			err.Error(),
+		)
+		return
+	}
+
+	plan.Name = types.StringValue(project.Name)
+	plan.refresh(project)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *azureProjectResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state azureProjectModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteAzureProject(ctx, state.ID.ValueInt64())
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error deleting Azure project",
+			err.Error(),
+		)
+		return
+	}
+}
+
+// refresh sets the attributes computed by nOps.
+func (m *azureProjectModel) refresh(project *AzureProject) {
+	m.ID = types.Int64Value(int64(project.ID))
+	m.ClientID = types.Int64Value(int64(project.Client))
+	m.Scope = types.StringValue(azureScope(project))
+	m.CreatedAt = serverTimestamp(project.CreatedAt)
+	m.UpdatedAt = serverTimestamp(project.UpdatedAt)
+	m.IntegratedAt = serverTimestamp(project.IntegratedAt)
+	if project.UpdatedAt != "" {
+		m.LastUpdated = serverTimestamp(project.UpdatedAt)
+	} else {
+		m.LastUpdated = types.StringValue(time.Now().Format(time.RFC3339))
+	}
+}
+
+// azureScope returns the Azure scope of the subscription or billing account onboarded by the project.
+func azureScope(project *AzureProject) string {
+	if project.SubscriptionID != "" {
+		return "/subscriptions/" + project.SubscriptionID
+	}
+
+	return "/providers/Microsoft.Billing/billingAccounts/" + project.BillingAccountID
+}
+
+// azureOptionalID returns the identifier, null when nOps reports it empty.
+func azureOptionalID(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+
+	return types.StringValue(value)
+}
@@ -0,0 +1,136 @@
+package nops
+
+import (
+	"context"
+	"testing"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	testAzureTenantID       = "8f3c2b1a-4d5e-4f60-9a7b-1c2d3e4f5a6b"
+	testAzureSubscriptionID = "0b1c2d3e-4f5a-4b6c-8d7e-9f0a1b2c3d4e"
+)
+
+func testAzureProjectModel() azureProjectModel {
+	return azureProjectModel{
+		ID:               types.Int64Unknown(),
+		LastUpdated:      types.StringUnknown(),
+		Name:             types.StringValue("azure-production"),
+		TenantID:         types.StringValue(testAzureTenantID),
+		SubscriptionID:   types.StringValue(testAzureSubscriptionID),
+		BillingAccountID: types.StringNull(),
+		Scope:            types.StringUnknown(),
+		ClientID:         types.Int64Unknown(),
+		CreatedAt:        types.StringUnknown(),
+		UpdatedAt:        types.StringUnknown(),
+		IntegratedAt:     types.StringUnknown(),
+	}
+}
+
+func TestAzureProjectResourceValidateConfig(t *testing.T) {
+	cases := map[string]struct {
+		update         func(*azureProjectModel)
+		expectedErrors int
+	}{
+		"subscription": {update: func(m *azureProjectModel) {}},
+		"billing account": {update: func(m *azureProjectModel) {
+			m.SubscriptionID, m.BillingAccountID = types.StringNull(), types.StringValue("12345678")
+		}},
+		"both scopes":          {update: func(m *azureProjectModel) { m.BillingAccountID = types.StringValue("12345678") }, expectedErrors: 1},
+		"no scope":             {update: func(m *azureProjectModel) { m.SubscriptionID = types.StringNull() }, expectedErrors: 1},
+		"invalid tenant":       {update: func(m *azureProjectModel) { m.TenantID = types.StringValue("contoso.onmicrosoft.com") }, expectedErrors: 1},
+		"invalid subscription": {update: func(m *azureProjectModel) { m.SubscriptionID = types.StringValue("production") }, expectedErrors: 1},
+		"unknown subscription": {update: func(m *azureProjectModel) { m.SubscriptionID = types.StringUnknown() }},
+		"empty billing account": {update: func(m *azureProjectModel) {
+			m.SubscriptionID, m.BillingAccountID = types.StringNull(), types.StringValue("")
+		}, expectedErrors: 1},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, s := newTestResource(t, newFakeNops(), &azureProjectResource{})
+			config := testAzureProjectModel()
+			c.update(&config)
+
+			var resp fwresource.ValidateConfigResponse
+			r.ValidateConfig(context.Background(), fwresource.ValidateConfigRequest{Config: tfsdk.Config(testResourceState(t, s, &config))}, &resp)
+			if resp.Diagnostics.ErrorsCount() != c.expectedErrors {
+				t.Errorf("expected %d errors, got %v", c.expectedErrors, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestAzureProjectResourceLifecycle(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops()
+	r, s := newTestResource(t, fake, &azureProjectResource{})
+
+	plan := testAzureProjectModel()
+	createResp := fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}, &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error creating Azure project: %v", createResp.Diagnostics)
+	}
+
+	var state azureProjectModel
+	createResp.Diagnostics.Append(createResp.State.Get(ctx, &state)...)
+	if state.ID.ValueInt64() != 1 || state.ClientID.ValueInt64() != 1000 || state.Scope.ValueString() != "/subscriptions/"+testAzureSubscriptionID {
+		t.Errorf("expected project 1 for the subscription scope, got %s for %s", state.ID, state.Scope)
+	}
+	if !state.IntegratedAt.IsNull() || state.LastUpdated.ValueString() != "2024-10-21T15:04:05Z" {
+		t.Errorf("expected a pending integration updated by nOps, got %s and %s", state.IntegratedAt, state.LastUpdated)
+	}
+
+	plan = state
+	plan.Name = types.StringValue("azure-prod")
+	updateResp := fwresource.UpdateResponse{State: createResp.State}
+	r.Update(ctx, fwresource.UpdateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan)), State: createResp.State}, &updateResp)
+	if updateResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error updating Azure project: %v", updateResp.Diagnostics)
+	}
+	if project := fake.azureProjects[1]; project.Name != "azure-prod" {
+		t.Errorf("expected the project to be renamed in nOps, got %q", project.Name)
+	}
+
+	deleteResp := fwresource.DeleteResponse{State: updateResp.State}
+	r.Delete(ctx, fwresource.DeleteRequest{State: updateResp.State}, &deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error deleting Azure project: %v", deleteResp.Diagnostics)
+	}
+	if len(fake.azureProjects) != 0 {
+		t.Errorf("expected the project to be deleted from nOps")
+	}
+}
+
+func TestAzureProjectResourceReadBillingAccount(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops()
+	fake.azureProjects[5] = AzureProject{ID: 5, Client: 1000, Name: "azure-billing", TenantID: testAzureTenantID, BillingAccountID: "12345678"}
+	r, s := newTestResource(t, fake, &azureProjectResource{})
+
+	model := azureProjectModel{ID: types.Int64Value(5)}
+	resp := fwresource.ReadResponse{State: testResourceState(t, s, &model)}
+	r.Read(ctx, fwresource.ReadRequest{State: resp.State}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error reading Azure project: %v", resp.Diagnostics)
+	}
+
+	var state azureProjectModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if !state.SubscriptionID.IsNull() || state.BillingAccountID.ValueString() != "12345678" {
+		t.Errorf("expected the billing account scope, got %s and %s", state.SubscriptionID, state.BillingAccountID)
+	}
+	if state.Scope.ValueString() != "/providers/Microsoft.Billing/billingAccounts/12345678" {
+		t.Errorf("expected the billing account scope, got %s", state.Scope)
+	}
+
+	delete(fake.azureProjects, 5)
+	r.Read(ctx, fwresource.ReadRequest{State: resp.State}, &resp)
+	if resp.Diagnostics.HasError() || !resp.State.Raw.IsNull() {
+		t.Errorf("expected the project deleted in nOps to be removed from state, got %v", resp.Diagnostics)
+	}
+}
@@ -0,0 +1,398 @@
+package nops
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &commitmentManagementResource{}
+	_ resource.ResourceWithConfigure      = &commitmentManagementResource{}
+	_ resource.ResourceWithImportState    = &commitmentManagementResource{}
+	_ resource.ResourceWithValidateConfig = &commitmentManagementResource{}
+)
+
+var (
+	// commitmentRiskTolerances are the risk tolerances nOps purchases commitments with, from the least to the most committed.
+	commitmentRiskTolerances = []string{"conservative", "balanced", "aggressive"}
+	// commitmentTermLengths are the commitment terms offered by AWS, in years.
+	commitmentTermLengths = []int64{1, 3}
+	// commitmentPaymentOptions are the payment options offered by AWS for commitments.
+	commitmentPaymentOptions = []string{"no_upfront", "partial_upfront", "all_upfront"}
+)
+
+// commitmentManagementResource is the resource implementation.
+type commitmentManagementResource struct {
+	client *Client
+}
+
+type commitmentManagementModel struct {
+	ID               types.Int64  `tfsdk:"id"`
+	LastUpdated      types.String `tfsdk:"last_updated"`
+	ProjectID        types.Int64  `tfsdk:"project_id"`
+	Enabled          types.Bool   `tfsdk:"enabled"`
+	RiskTolerance    types.String `tfsdk:"risk_tolerance"`
+	TermLengths      types.Set    `tfsdk:"term_lengths"`
+	PaymentOptions   types.Set    `tfsdk:"payment_options"`
+	ExcludedServices types.Set    `tfsdk:"excluded_services"`
+}
+
+// NewCommitmentManagementResource is a helper function to simplify the provider implementation.
+func NewCommitmentManagementResource() resource.Resource {
+	return &commitmentManagementResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *commitmentManagementResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *commitmentManagementResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_commitment_management"
+}
+
+// Schema defines the schema for the resource.
+func (r *commitmentManagementResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Configures how nOps manages the Savings Plans and Reserved Instances of a payer project: the risk tolerance, " +
+			"the terms and payment options it may purchase, and the services it leaves alone. " +
+			"A payer has a single set of settings, settings that already exist in nOps must be imported instead of created.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Identifier of the commitment management settings, the nOps project identifier",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the resource was last updated, as reported by nOps",
+			},
+			"project_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "nOps project identifier of the payer account, usually `nops_project.id`. The provider refuses linked accounts only when nOps reports their master payer account, it can't tell the others apart from payers",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"enabled": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Whether nOps purchases commitments for the payer, set it to `false` to pause purchases and keep the settings. Defaults to `true`",
+			},
+			"risk_tolerance": schema.StringAttribute{
+				Required:    true,
+				Description: "How much of the usage nOps covers with commitments, one of `conservative`, `balanced` or `aggressive`",
+			},
+			"term_lengths": schema.SetAttribute{
+				Required:    true,
+				ElementType: types.Int64Type,
+				Description: "Terms nOps may purchase commitments for, in years, `1` or `3`",
+			},
+			"payment_options": schema.SetAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				Description: "Payment options nOps may purchase commitments with, `no_upfront`, `partial_upfront` or `all_upfront`",
+			},
+			"excluded_services": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "AWS services nOps doesn't purchase commitments for, such as `AmazonRDS` or `AmazonElastiCache`",
+			},
+		},
+	}
+}
+
+// ValidateConfig validates the settings are among the ones supported by nOps.
+func (r *commitmentManagementResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config commitmentManagementModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.RiskTolerance.IsNull() && !config.RiskTolerance.IsUnknown() && !slices.Contains(commitmentRiskTolerances, config.RiskTolerance.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("risk_tolerance"),
+			"Invalid risk tolerance",
+			fmt.Sprintf("%q is not supported, expected one of %s", config.RiskTolerance.ValueString(), strings.Join(commitmentRiskTolerances, ", ")),
+		)
+	}
+
+	if !config.TermLengths.IsNull() && !config.TermLengths.IsUnknown() {
+		var terms []types.Int64
+		resp.Diagnostics.Append(config.TermLengths.ElementsAs(ctx, &terms, false)...)
+		if len(terms) == 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("term_lengths"), "Missing commitment terms", "At least one term length must be allowed")
+		}
+		for _, term := range terms {
+			if !term.IsUnknown() && !slices.Contains(commitmentTermLengths, term.ValueInt64()) {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("term_lengths"),
+					"Invalid commitment term",
+					fmt.Sprintf("%d years is not supported, commitments are purchased for 1 or 3 years", term.ValueInt64()),
+				)
+			}
+		}
+	}
+
+	if !config.PaymentOptions.IsNull() && !config.PaymentOptions.IsUnknown() {
+		var options []types.String
+		resp.Diagnostics.Append(config.PaymentOptions.ElementsAs(ctx, &options, false)...)
+		if len(options) == 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("payment_options"), "Missing payment options", "At least one payment option must be allowed")
+		}
+		for _, option := range options {
+			if !option.IsUnknown() && !slices.Contains(commitmentPaymentOptions, option.ValueString()) {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("payment_options"),
+					"Invalid payment option",
+					fmt.Sprintf("%q is not supported, expected one of %s", option.ValueString(), strings.Join(commitmentPaymentOptions, ", ")),
+				)
+			}
+		}
+	}
+
+	if !config.ExcludedServices.IsUnknown() {
+		var services []types.String
+		resp.Diagnostics.Append(config.ExcludedServices.ElementsAs(ctx, &services, false)...)
+		for _, service := range services {
+			if !service.IsUnknown() && strings.TrimSpace(service.ValueString()) == "" {
+				resp.Diagnostics.AddAttributeError(path.Root("excluded_services"), "Invalid excluded service", "Service names must not be empty")
+			}
+		}
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *commitmentManagementResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan commitmentManagementModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Commitments are purchased by the payer account, linked accounts share them. Linked accounts are
+	// only caught here when nOps reports their master payer account.
+	projects, err := r.client.GetProjects(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote project data",
+			err.Error(),
+		)
+		return
+	}
+	if _, err := lookupPayerProjectByID(projects, plan.ProjectID.ValueInt64()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("project_id"), "Invalid nOps project", err.Error())
+		return
+	}
+
+	// The settings are a singleton of the payer, don't overwrite the ones set up in nOps or by another resource.
+	_, err = r.client.GetCommitmentManagement(ctx, plan.ProjectID.ValueInt64())
+	if err == nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("project_id"),
+			"Error: commitment management is already configured for this project",
+			fmt.Sprintf("Project %d already has commitment management settings in nOps, please import them with the project ID %d instead of creating them", plan.ProjectID.ValueInt64(), plan.ProjectID.ValueInt64()),
+		)
+		return
+	}
+	if !IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error getting remote commitment management settings",
+			err.Error(),
+		)
+		return
+	}
+
+	settings, diags := plan.settings(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updated, err := r.client.PutCommitmentManagement(ctx, plan.ProjectID.ValueInt64(), settings)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error configuring commitment management",
+			fmt.Sprintf("nOps rejected the commitment management settings of project %d: %s", plan.ProjectID.ValueInt64(), err.Error()),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("Commitment management configured for project %d", plan.ProjectID.ValueInt64()))
+	plan.ID = plan.ProjectID
+	plan.refresh(updated)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *commitmentManagementResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state commitmentManagementModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	settings, err := r.client.GetCommitmentManagement(ctx, state.ID.ValueInt64())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, fmt.Sprintf("No commitment management settings found in nOps for project %d, removing them from state", state.ID.ValueInt64()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote commitment management settings",
+			err.Error(),
+		)
+		return
+	}
+
+	state.ProjectID = state.ID
+	state.Enabled = types.BoolValue(settings.Enabled)
+	state.RiskTolerance = types.StringValue(settings.RiskTolerance)
+	var d diag.Diagnostics
+	state.TermLengths, d = types.SetValueFrom(ctx, types.Int64Type, settings.TermLengths)
+	resp.Diagnostics.Append(d...)
+	state.PaymentOptions, d = types.SetValueFrom(ctx, types.StringType, settings.PaymentOptions)
+	resp.Diagnostics.Append(d...)
+	// Keeps an unset excluded_services null while nOps reports no excluded service.
+	if len(settings.ExcludedServices) > 0 || !state.ExcludedServices.IsNull() {
+		state.ExcludedServices, d = types.SetValueFrom(ctx, types.StringType, settings.ExcludedServices)
+		resp.Diagnostics.Append(d...)
+	}
+	state.refresh(settings)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *commitmentManagementResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The settings are imported with the identifier of their payer project.
+	val, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Error parsing ID for import, please check for a correct project ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), val)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *commitmentManagementResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan commitmentManagementModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	settings, diags := plan.settings(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updated, err := r.client.PutCommitmentManagement(ctx, plan.ProjectID.ValueInt64(), settings)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating commitment management",
+			fmt.Sprintf("nOps rejected the commitment management settings of project %d: %s", plan.ProjectID.ValueInt64(), err.Error()),
+		)
+		return
+	}
+
+	plan.ID = plan.ProjectID
+	plan.refresh(updated)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *commitmentManagementResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state commitmentManagementModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteCommitmentManagement(ctx, state.ID.ValueInt64())
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error deleting commitment management settings",
+			err.Error(),
+		)
+		return
+	}
+}
+
+// settings returns the nOps payload for the planned settings.
+func (m *commitmentManagementModel) settings(ctx context.Context) (CommitmentManagement, diag.Diagnostics) {
+	settings := CommitmentManagement{
+		Enabled:          m.Enabled.ValueBool(),
+		RiskTolerance:    m.RiskTolerance.ValueString(),
+		ExcludedServices: []string{},
+	}
+
+	var diags diag.Diagnostics
+	diags.Append(m.TermLengths.ElementsAs(ctx, &settings.TermLengths, false)...)
+	diags.Append(m.PaymentOptions.ElementsAs(ctx, &settings.PaymentOptions, false)...)
+	if !m.ExcludedServices.IsNull() {
+		diags.Append(m.ExcludedServices.ElementsAs(ctx, &settings.ExcludedServices, false)...)
+	}
+	sort.Slice(settings.TermLengths, func(i, j int) bool { return settings.TermLengths[i] < settings.TermLengths[j] })
+	sort.Strings(settings.PaymentOptions)
+	sort.Strings(settings.ExcludedServices)
+
+	return settings, diags
+}
+
+// refresh sets the attributes computed by nOps.
+func (m *commitmentManagementModel) refresh(settings *CommitmentManagement) {
+	if settings.UpdatedAt != "" {
+		m.LastUpdated = serverTimestamp(settings.UpdatedAt)
+	} else if m.LastUpdated.IsNull() || m.LastUpdated.IsUnknown() {
+		m.LastUpdated = types.StringValue(time.Now().Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,174 @@
+package nops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testCommitmentManagementModel(t *testing.T) commitmentManagementModel {
+	t.Helper()
+
+	return commitmentManagementModel{
+		ID:               types.Int64Unknown(),
+		LastUpdated:      types.StringUnknown(),
+		ProjectID:        types.Int64Value(1),
+		Enabled:          types.BoolValue(true),
+		RiskTolerance:    types.StringValue("balanced"),
+		TermLengths:      testSet(t, types.Int64Type, []int64{1}),
+		PaymentOptions:   testSet(t, types.StringType, []string{"no_upfront", "partial_upfront"}),
+		ExcludedServices: types.SetNull(types.StringType),
+	}
+}
+
+// testSet returns a set value of the element type holding the elements.
+func testSet[T any](t *testing.T, elementType attr.Type, elements []T) types.Set {
+	t.Helper()
+
+	value, diags := types.SetValueFrom(context.Background(), elementType, elements)
+	if diags.HasError() {
+		t.Fatalf("unexpected error building set: %v", diags)
+	}
+
+	return value
+}
+
+func TestCommitmentManagementResourceValidateConfig(t *testing.T) {
+	cases := map[string]struct {
+		update         func(*commitmentManagementModel)
+		expectedErrors int
+	}{
+		"valid": {update: func(m *commitmentManagementModel) {}},
+		"excluded services": {update: func(m *commitmentManagementModel) {
+			m.ExcludedServices = testSet(t, types.StringType, []string{"AmazonRDS", "AmazonElastiCache"})
+		}},
+		"invalid risk tolerance": {update: func(m *commitmentManagementModel) { m.RiskTolerance = types.StringValue("moderate") }, expectedErrors: 1},
+		"invalid term":           {update: func(m *commitmentManagementModel) { m.TermLengths = testSet(t, types.Int64Type, []int64{1, 2}) }, expectedErrors: 1},
+		"no term":                {update: func(m *commitmentManagementModel) { m.TermLengths = testSet(t, types.Int64Type, []int64{}) }, expectedErrors: 1},
+		"invalid payment option": {update: func(m *commitmentManagementModel) {
+			m.PaymentOptions = testSet(t, types.StringType, []string{"upfront"})
+		}, expectedErrors: 1},
+		"no payment option": {update: func(m *commitmentManagementModel) {
+			m.PaymentOptions = testSet(t, types.StringType, []string{})
+		}, expectedErrors: 1},
+		"empty excluded service": {update: func(m *commitmentManagementModel) {
+			m.ExcludedServices = testSet(t, types.StringType, []string{""})
+		}, expectedErrors: 1},
+		"unknown terms": {update: func(m *commitmentManagementModel) { m.TermLengths = types.SetUnknown(types.Int64Type) }},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, s := newTestResource(t, newFakeNops(), &commitmentManagementResource{})
+			config := testCommitmentManagementModel(t)
+			c.update(&config)
+
+			var resp fwresource.ValidateConfigResponse
+			r.ValidateConfig(context.Background(), fwresource.ValidateConfigRequest{Config: tfsdk.Config(testResourceState(t, s, &config))}, &resp)
+			if resp.Diagnostics.ErrorsCount() != c.expectedErrors {
+				t.Errorf("expected %d errors, got %v", c.expectedErrors, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestCommitmentManagementResourceLifecycle(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(Project{ID: 1, AccountNumber: "580010171808", MasterPayerAccountNumber: "580010171808"})
+	r, s := newTestResource(t, fake, &commitmentManagementResource{})
+
+	plan := testCommitmentManagementModel(t)
+	createResp := fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}, &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error configuring commitment management: %v", createResp.Diagnostics)
+	}
+
+	var state commitmentManagementModel
+	createResp.Diagnostics.Append(createResp.State.Get(ctx, &state)...)
+	if state.ID.ValueInt64() != 1 || state.LastUpdated.ValueString() != "2024-10-21T15:04:05Z" {
+		t.Errorf("expected the settings of project 1 updated by nOps, got %s updated at %s", state.ID, state.LastUpdated)
+	}
+	settings := fake.commitments[1]
+	if settings.RiskTolerance != "balanced" || len(settings.TermLengths) != 1 || len(settings.PaymentOptions) != 2 || len(settings.ExcludedServices) != 0 {
+		t.Errorf("expected the planned settings to be sent to nOps, got %+v", settings)
+	}
+
+	plan = state
+	plan.RiskTolerance = types.StringValue("conservative")
+	plan.ExcludedServices = testSet(t, types.StringType, []string{"AmazonRDS"})
+	updateResp := fwresource.UpdateResponse{State: createResp.State}
+	r.Update(ctx, fwresource.UpdateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan)), State: createResp.State}, &updateResp)
+	if updateResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error updating commitment management: %v", updateResp.Diagnostics)
+	}
+	if settings := fake.commitments[1]; settings.RiskTolerance != "conservative" || len(settings.ExcludedServices) != 1 {
+		t.Errorf("expected the settings to be updated in nOps, got %+v", settings)
+	}
+
+	deleteResp := fwresource.DeleteResponse{State: updateResp.State}
+	r.Delete(ctx, fwresource.DeleteRequest{State: updateResp.State}, &deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error deleting commitment management: %v", deleteResp.Diagnostics)
+	}
+	if len(fake.commitments) != 0 {
+		t.Errorf("expected the settings to be deleted from nOps")
+	}
+}
+
+func TestCommitmentManagementResourceCreateLinkedAccount(t *testing.T) {
+	fake := newFakeNops(Project{ID: 1, AccountNumber: "580010171808", MasterPayerAccountNumber: "123456789012"})
+	r, s := newTestResource(t, fake, &commitmentManagementResource{})
+
+	plan := testCommitmentManagementModel(t)
+	resp := fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(context.Background(), fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("expected an error managing the commitments of a linked account")
+	}
+	if len(fake.commitments) != 0 {
+		t.Errorf("expected no settings to be sent to nOps")
+	}
+}
+
+func TestCommitmentManagementResourceReadDrift(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(Project{ID: 1, AccountNumber: "580010171808"})
+	fake.commitments[1] = CommitmentManagement{
+		Enabled:          true,
+		RiskTolerance:    "aggressive",
+		TermLengths:      []int64{1, 3},
+		PaymentOptions:   []string{"all_upfront"},
+		ExcludedServices: []string{},
+	}
+	r, s := newTestResource(t, fake, &commitmentManagementResource{})
+
+	// Imported settings only have their identifier in state.
+	state := nullResourceState(s)
+	state.SetAttribute(ctx, path.Root("id"), int64(1))
+	resp := fwresource.ReadResponse{State: state}
+	r.Read(ctx, fwresource.ReadRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error reading commitment management: %v", resp.Diagnostics)
+	}
+
+	var model commitmentManagementModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &model)...)
+	if model.ProjectID.ValueInt64() != 1 || model.RiskTolerance.ValueString() != "aggressive" || !model.ExcludedServices.IsNull() {
+		t.Errorf("expected the imported settings to be refreshed, got %+v", model)
+	}
+	if !model.TermLengths.Equal(testSet(t, types.Int64Type, []int64{3, 1})) || !model.PaymentOptions.Equal(testSet(t, types.StringType, []string{"all_upfront"})) {
+		t.Errorf("expected the terms and payment options set in the nOps UI, got %s and %s", model.TermLengths, model.PaymentOptions)
+	}
+
+	delete(fake.commitments, 1)
+	r.Read(ctx, fwresource.ReadRequest{State: resp.State}, &resp)
+	if !resp.State.Raw.IsNull() {
+		t.Errorf("expected the settings deleted in nOps to be removed from state")
+	}
+}
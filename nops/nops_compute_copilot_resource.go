@@ -0,0 +1,462 @@
+package nops
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &computeCopilotResource{}
+	_ resource.ResourceWithConfigure      = &computeCopilotResource{}
+	_ resource.ResourceWithImportState    = &computeCopilotResource{}
+	_ resource.ResourceWithValidateConfig = &computeCopilotResource{}
+)
+
+// computeCopilotStatusFailed is the status reported by nOps when some targets can't be managed.
+const computeCopilotStatusFailed = "failed"
+
+var (
+	// autoScalingGroupNamePattern matches the Auto Scaling Group naming rules, names can't contain colons.
+	autoScalingGroupNamePattern = regexp.MustCompile(`^[^:]{1,255}$`)
+	// eksNodeGroupNamePattern matches the EKS managed node group naming rules.
+	eksNodeGroupNamePattern = regexp.MustCompile(`^[0-9A-Za-z][A-Za-z0-9\-_]{0,62}$`)
+)
+
+// computeCopilotResource is the resource implementation.
+type computeCopilotResource struct {
+	client *Client
+}
+
+type computeCopilotModel struct {
+	ID                   types.Int64  `tfsdk:"id"`
+	LastUpdated          types.String `tfsdk:"last_updated"`
+	ProjectID            types.Int64  `tfsdk:"project_id"`
+	Enabled              types.Bool   `tfsdk:"enabled"`
+	AutoScalingGroups    types.Set    `tfsdk:"auto_scaling_groups"`
+	EKSNodeGroups        types.Set    `tfsdk:"eks_node_groups"`
+	SpotPercentage       types.Int64  `tfsdk:"spot_percentage"`
+	OnDemandBaseCapacity types.Int64  `tfsdk:"on_demand_base_capacity"`
+	Status               types.String `tfsdk:"status"`
+	StatusMessage        types.String `tfsdk:"status_message"`
+}
+
+type computeCopilotNodeGroupModel struct {
+	ClusterName   types.String `tfsdk:"cluster_name"`
+	NodeGroupName types.String `tfsdk:"node_group_name"`
+}
+
+// computeCopilotNodeGroupType is the object type of each element in the eks_node_groups attribute.
+var computeCopilotNodeGroupType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"cluster_name":    types.StringType,
+		"node_group_name": types.StringType,
+	},
+}
+
+// NewComputeCopilotResource is a helper function to simplify the provider implementation.
+func NewComputeCopilotResource() resource.Resource {
+	return &computeCopilotResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *computeCopilotResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *computeCopilotResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_compute_copilot"
+}
+
+// Schema defines the schema for the resource.
+func (r *computeCopilotResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Enables nOps Compute Copilot for a project and selects the Auto Scaling Groups and EKS node groups it manages, " +
+			"along with the share of their capacity it runs on spot instances. The project role needs the `NopsComputeCopilotPolicy` policy. " +
+			"A project has a single set of settings, settings that already exist in nOps must be imported instead of created.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Identifier of the Compute Copilot settings, the nOps project identifier",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the resource was last updated, as reported by nOps",
+			},
+			"project_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "nOps project identifier of the AWS account running the targets, usually `nops_project.id`",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"enabled": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Whether Compute Copilot manages the targets, set it to `false` to pause Copilot and keep its settings. Defaults to `true`",
+			},
+			"auto_scaling_groups": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Names of the Auto Scaling Groups managed by Compute Copilot",
+			},
+			"eks_node_groups": schema.SetNestedAttribute{
+				Optional:    true,
+				Description: "EKS managed node groups managed by Compute Copilot",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"cluster_name": schema.StringAttribute{
+							Required:    true,
+							Description: "Name of the EKS cluster of the node group",
+						},
+						"node_group_name": schema.StringAttribute{
+							Required:    true,
+							Description: "Name of the managed node group",
+						},
+					},
+				},
+			},
+			"spot_percentage": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(100),
+				Description: "Percentage of the capacity above `on_demand_base_capacity` Compute Copilot runs on spot instances, from 0 to 100. Defaults to `100`",
+			},
+			"on_demand_base_capacity": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(0),
+				Description: "Number of instances of each target always kept on-demand. Defaults to `0`",
+			},
+			"status": schema.StringAttribute{
+				Computed:    true,
+				Description: "Status of the Compute Copilot settings reported by nOps, such as `pending`, `active`, `paused` or `failed`",
+			},
+			"status_message": schema.StringAttribute{
+				Computed:    true,
+				Description: "Details about the status, explaining which targets can't be managed when `status` is `failed`",
+			},
+		},
+	}
+}
+
+// ValidateConfig validates the targets follow the AWS naming rules and the capacity split is in range.
+func (r *computeCopilotResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config computeCopilotModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if percentage := config.SpotPercentage; !percentage.IsNull() && !percentage.IsUnknown() && (percentage.ValueInt64() < 0 || percentage.ValueInt64() > 100) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("spot_percentage"),
+			"Invalid spot percentage",
+			fmt.Sprintf("%d must be between 0 and 100", percentage.ValueInt64()),
+		)
+	}
+	if capacity := config.OnDemandBaseCapacity; !capacity.IsNull() && !capacity.IsUnknown() && capacity.ValueInt64() < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("on_demand_base_capacity"),
+			"Invalid on-demand base capacity",
+			fmt.Sprintf("%d must not be negative", capacity.ValueInt64()),
+		)
+	}
+
+	if config.AutoScalingGroups.IsUnknown() || config.EKSNodeGroups.IsUnknown() {
+		return
+	}
+	var autoScalingGroups []types.String
+	resp.Diagnostics.Append(config.AutoScalingGroups.ElementsAs(ctx, &autoScalingGroups, false)...)
+	var nodeGroups []computeCopilotNodeGroupModel
+	resp.Diagnostics.Append(config.EKSNodeGroups.ElementsAs(ctx, &nodeGroups, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if len(autoScalingGroups) == 0 && len(nodeGroups) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("auto_scaling_groups"),
+			"Missing Compute Copilot targets",
+			"At least one of auto_scaling_groups or eks_node_groups must be set",
+		)
+	}
+	for _, name := range autoScalingGroups {
+		if !name.IsUnknown() && !autoScalingGroupNamePattern.MatchString(name.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("auto_scaling_groups"),
+				"Invalid Auto Scaling Group name",
+				fmt.Sprintf("%q is not a valid Auto Scaling Group name, it must be 1 to 255 characters without colons", name.ValueString()),
+			)
+		}
+	}
+	for _, nodeGroup := range nodeGroups {
+		if !nodeGroup.ClusterName.IsUnknown() && !eksClusterNamePattern.MatchString(nodeGroup.ClusterName.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("eks_node_groups"),
+				"Invalid EKS cluster name",
+				fmt.Sprintf("%q is not a valid cluster name, expected it to match %s", nodeGroup.ClusterName.ValueString(), eksClusterNamePattern),
+			)
+		}
+		if !nodeGroup.NodeGroupName.IsUnknown() && !eksNodeGroupNamePattern.MatchString(nodeGroup.NodeGroupName.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("eks_node_groups"),
+				"Invalid EKS node group name",
+				fmt.Sprintf("%q is not a valid node group name, expected it to match %s", nodeGroup.NodeGroupName.ValueString(), eksNodeGroupNamePattern),
+			)
+		}
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *computeCopilotResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan computeCopilotModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	settings, diags := plan.settings(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The settings are a singleton of the project, don't overwrite the ones set up in nOps or by another resource.
+	_, err := r.client.GetComputeCopilot(ctx, plan.ProjectID.ValueInt64())
+	if err == nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("project_id"),
+			"Error: Compute Copilot is already configured for this project",
+			fmt.Sprintf("Project %d already has Compute Copilot settings in nOps, please import them with the project ID %d instead of creating them", plan.ProjectID.ValueInt64(), plan.ProjectID.ValueInt64()),
+		)
+		return
+	}
+	if !IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error getting remote Compute Copilot settings",
+			err.Error(),
+		)
+		return
+	}
+
+	copilot, err := r.client.PutComputeCopilot(ctx, plan.ProjectID.ValueInt64(), settings)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error enabling Compute Copilot",
+			fmt.Sprintf("nOps rejected the Compute Copilot settings of project %d: %s", plan.ProjectID.ValueInt64(), err.Error()),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("Compute Copilot configured for project %d with status %s", plan.ProjectID.ValueInt64(), copilot.Status))
+	plan.ID = plan.ProjectID
+	plan.refresh(copilot)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *computeCopilotResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state computeCopilotModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	copilot, err := r.client.GetComputeCopilot(ctx, state.ID.ValueInt64())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, fmt.Sprintf("No Compute Copilot settings found in nOps for project %d, removing them from state", state.ID.ValueInt64()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote Compute Copilot settings",
+			err.Error(),
+		)
+		return
+	}
+
+	state.ProjectID = state.ID
+	state.Enabled = types.BoolValue(copilot.Enabled)
+	state.SpotPercentage = types.Int64Value(copilot.SpotPercentage)
+	state.OnDemandBaseCapacity = types.Int64Value(copilot.OnDemandBaseCapacity)
+	resp.Diagnostics.Append(state.refreshTargets(ctx, copilot)...)
+	state.refresh(copilot)
+	if copilot.Status == computeCopilotStatusFailed {
+		resp.Diagnostics.AddWarning(
+			fmt.Sprintf("Compute Copilot can't manage some targets of project %d", state.ID.ValueInt64()),
+			copilot.StatusMessage,
+		)
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *computeCopilotResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The settings are imported with the identifier of their project.
+	val, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Error parsing ID for import, please check for a correct project ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), val)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *computeCopilotResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan computeCopilotModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	settings, diags := plan.settings(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	copilot, err := r.client.PutComputeCopilot(ctx, plan.ProjectID.ValueInt64(), settings)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating Compute Copilot",
+			fmt.Sprintf("nOps rejected the Compute Copilot settings of project %d: %s", plan.ProjectID.ValueInt64(), err.Error()),
+		)
+		return
+	}
+
+	plan.ID = plan.ProjectID
+	plan.refresh(copilot)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *computeCopilotResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state computeCopilotModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteComputeCopilot(ctx, state.ID.ValueInt64())
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error disabling Compute Copilot",
+			err.Error(),
+		)
+		return
+	}
+}
+
+// settings returns the nOps payload for the planned settings.
+func (m *computeCopilotModel) settings(ctx context.Context) (ComputeCopilot, diag.Diagnostics) {
+	settings := ComputeCopilot{
+		Enabled:              m.Enabled.ValueBool(),
+		AutoScalingGroups:    []string{},
+		EKSNodeGroups:        []ComputeCopilotNodeGroup{},
+		SpotPercentage:       m.SpotPercentage.ValueInt64(),
+		OnDemandBaseCapacity: m.OnDemandBaseCapacity.ValueInt64(),
+	}
+
+	var diags diag.Diagnostics
+	if !m.AutoScalingGroups.IsNull() {
+		diags.Append(m.AutoScalingGroups.ElementsAs(ctx, &settings.AutoScalingGroups, false)...)
+		sort.Strings(settings.AutoScalingGroups)
+	}
+	if !m.EKSNodeGroups.IsNull() {
+		var nodeGroups []computeCopilotNodeGroupModel
+		diags.Append(m.EKSNodeGroups.ElementsAs(ctx, &nodeGroups, false)...)
+		for _, nodeGroup := range nodeGroups {
+			settings.EKSNodeGroups = append(settings.EKSNodeGroups, ComputeCopilotNodeGroup{
+				ClusterName:   nodeGroup.ClusterName.ValueString(),
+				NodeGroupName: nodeGroup.NodeGroupName.ValueString(),
+			})
+		}
+	}
+
+	return settings, diags
+}
+
+// refreshTargets sets the targets managed by Compute Copilot, keeping an unset attribute null while nOps
+// reports no target of its kind.
+func (m *computeCopilotModel) refreshTargets(ctx context.Context, copilot *ComputeCopilot) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if len(copilot.AutoScalingGroups) > 0 || !m.AutoScalingGroups.IsNull() {
+		var d diag.Diagnostics
+		m.AutoScalingGroups, d = types.SetValueFrom(ctx, types.StringType, copilot.AutoScalingGroups)
+		diags.Append(d...)
+	}
+	if len(copilot.EKSNodeGroups) > 0 || !m.EKSNodeGroups.IsNull() {
+		nodeGroups := make([]computeCopilotNodeGroupModel, 0, len(copilot.EKSNodeGroups))
+		for _, nodeGroup := range copilot.EKSNodeGroups {
+			nodeGroups = append(nodeGroups, computeCopilotNodeGroupModel{
+				ClusterName:   types.StringValue(nodeGroup.ClusterName),
+				NodeGroupName: types.StringValue(nodeGroup.NodeGroupName),
+			})
+		}
+		var d diag.Diagnostics
+		m.EKSNodeGroups, d = types.SetValueFrom(ctx, computeCopilotNodeGroupType, nodeGroups)
+		diags.Append(d...)
+	}
+
+	return diags
+}
+
+// refresh sets the attributes computed by nOps.
+func (m *computeCopilotModel) refresh(copilot *ComputeCopilot) {
+	m.Status = types.StringValue(copilot.Status)
+	m.StatusMessage = types.StringValue(copilot.StatusMessage)
+	if copilot.UpdatedAt != "" {
+		m.LastUpdated = serverTimestamp(copilot.UpdatedAt)
+	} else if m.LastUpdated.IsNull() || m.LastUpdated.IsUnknown() {
+		m.LastUpdated = types.StringValue(time.Now().Format(time.RFC3339))
+	}
+}
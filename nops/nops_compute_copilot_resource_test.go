@@ -0,0 +1,192 @@
+package nops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testComputeCopilotModel(t *testing.T) computeCopilotModel {
+	t.Helper()
+
+	return computeCopilotModel{
+		ID:                   types.Int64Unknown(),
+		LastUpdated:          types.StringUnknown(),
+		ProjectID:            types.Int64Value(1),
+		Enabled:              types.BoolValue(true),
+		AutoScalingGroups:    testAutoScalingGroups(t, "web", "workers"),
+		EKSNodeGroups:        types.SetNull(computeCopilotNodeGroupType),
+		SpotPercentage:       types.Int64Value(80),
+		OnDemandBaseCapacity: types.Int64Value(1),
+		Status:               types.StringUnknown(),
+		StatusMessage:        types.StringUnknown(),
+	}
+}
+
+// testAutoScalingGroups returns an auto_scaling_groups value with the names.
+func testAutoScalingGroups(t *testing.T, names ...string) types.Set {
+	t.Helper()
+
+	value, diags := types.SetValueFrom(context.Background(), types.StringType, names)
+	if diags.HasError() {
+		t.Fatalf("unexpected error building Auto Scaling Groups: %v", diags)
+	}
+
+	return value
+}
+
+// testEKSNodeGroups returns an eks_node_groups value with the node groups of the production cluster.
+func testEKSNodeGroups(t *testing.T, names ...string) types.Set {
+	t.Helper()
+
+	nodeGroups := make([]computeCopilotNodeGroupModel, 0, len(names))
+	for _, name := range names {
+		nodeGroups = append(nodeGroups, computeCopilotNodeGroupModel{ClusterName: types.StringValue("production"), NodeGroupName: types.StringValue(name)})
+	}
+	value, diags := types.SetValueFrom(context.Background(), computeCopilotNodeGroupType, nodeGroups)
+	if diags.HasError() {
+		t.Fatalf("unexpected error building EKS node groups: %v", diags)
+	}
+
+	return value
+}
+
+func TestComputeCopilotResourceValidateConfig(t *testing.T) {
+	cases := map[string]struct {
+		update         func(*computeCopilotModel)
+		expectedErrors int
+	}{
+		"auto scaling groups": {update: func(m *computeCopilotModel) {}},
+		"node groups": {update: func(m *computeCopilotModel) {
+			m.AutoScalingGroups, m.EKSNodeGroups = types.SetNull(types.StringType), testEKSNodeGroups(t, "general")
+		}},
+		"no target": {update: func(m *computeCopilotModel) {
+			m.AutoScalingGroups = types.SetNull(types.StringType)
+		}, expectedErrors: 1},
+		"empty targets":        {update: func(m *computeCopilotModel) { m.AutoScalingGroups = testAutoScalingGroups(t) }, expectedErrors: 1},
+		"unknown targets":      {update: func(m *computeCopilotModel) { m.AutoScalingGroups = types.SetUnknown(types.StringType) }},
+		"invalid group":        {update: func(m *computeCopilotModel) { m.AutoScalingGroups = testAutoScalingGroups(t, "web:blue") }, expectedErrors: 1},
+		"invalid node group":   {update: func(m *computeCopilotModel) { m.EKSNodeGroups = testEKSNodeGroups(t, "-general") }, expectedErrors: 1},
+		"spot over 100":        {update: func(m *computeCopilotModel) { m.SpotPercentage = types.Int64Value(101) }, expectedErrors: 1},
+		"on-demand only":       {update: func(m *computeCopilotModel) { m.SpotPercentage = types.Int64Value(0) }},
+		"negative base":        {update: func(m *computeCopilotModel) { m.OnDemandBaseCapacity = types.Int64Value(-1) }, expectedErrors: 1},
+		"unknown spot percent": {update: func(m *computeCopilotModel) { m.SpotPercentage = types.Int64Unknown() }},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, s := newTestResource(t, newFakeNops(), &computeCopilotResource{})
+			config := testComputeCopilotModel(t)
+			c.update(&config)
+
+			var resp fwresource.ValidateConfigResponse
+			r.ValidateConfig(context.Background(), fwresource.ValidateConfigRequest{Config: tfsdk.Config(testResourceState(t, s, &config))}, &resp)
+			if resp.Diagnostics.ErrorsCount() != c.expectedErrors {
+				t.Errorf("expected %d errors, got %v", c.expectedErrors, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestComputeCopilotResourceLifecycle(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(Project{ID: 1, AccountNumber: "580010171808"})
+	r, s := newTestResource(t, fake, &computeCopilotResource{})
+
+	plan := testComputeCopilotModel(t)
+	createResp := fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}, &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error enabling Compute Copilot: %v", createResp.Diagnostics)
+	}
+
+	var state computeCopilotModel
+	createResp.Diagnostics.Append(createResp.State.Get(ctx, &state)...)
+	if state.ID.ValueInt64() != 1 || state.Status.ValueString() != "active" || state.LastUpdated.ValueString() != "2024-10-21T15:04:05Z" {
+		t.Errorf("expected active settings of project 1, got %s with status %s", state.ID, state.Status)
+	}
+	copilot := fake.computeCopilots[1]
+	if len(copilot.AutoScalingGroups) != 2 || len(copilot.EKSNodeGroups) != 0 || copilot.SpotPercentage != 80 || copilot.OnDemandBaseCapacity != 1 {
+		t.Errorf("expected the planned settings to be sent to nOps, got %+v", copilot)
+	}
+
+	// Moving to node groups and pausing Copilot updates the settings in place.
+	plan = state
+	plan.Enabled = types.BoolValue(false)
+	plan.AutoScalingGroups = types.SetNull(types.StringType)
+	plan.EKSNodeGroups = testEKSNodeGroups(t, "general", "batch")
+	updateResp := fwresource.UpdateResponse{State: createResp.State}
+	r.Update(ctx, fwresource.UpdateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan)), State: createResp.State}, &updateResp)
+	if updateResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error updating Compute Copilot: %v", updateResp.Diagnostics)
+	}
+	copilot = fake.computeCopilots[1]
+	if copilot.Enabled || len(copilot.AutoScalingGroups) != 0 || len(copilot.EKSNodeGroups) != 2 {
+		t.Errorf("expected paused settings targeting the node groups, got %+v", copilot)
+	}
+
+	// Reading it back reports no drift.
+	readResp := fwresource.ReadResponse{State: updateResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: updateResp.State}, &readResp)
+	readResp.Diagnostics.Append(readResp.State.Get(ctx, &state)...)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error reading Compute Copilot: %v", readResp.Diagnostics)
+	}
+	if !state.AutoScalingGroups.IsNull() || !state.EKSNodeGroups.Equal(plan.EKSNodeGroups) || state.Status.ValueString() != "paused" {
+		t.Errorf("expected the paused node group settings, got %s and %s with status %s", state.AutoScalingGroups, state.EKSNodeGroups, state.Status)
+	}
+
+	deleteResp := fwresource.DeleteResponse{State: readResp.State}
+	r.Delete(ctx, fwresource.DeleteRequest{State: readResp.State}, &deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error disabling Compute Copilot: %v", deleteResp.Diagnostics)
+	}
+	if len(fake.computeCopilots) != 0 {
+		t.Errorf("expected Compute Copilot to be disabled in nOps")
+	}
+}
+
+func TestComputeCopilotResourceReadFailed(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(Project{ID: 1, AccountNumber: "580010171808"})
+	fake.computeCopilots[1] = ComputeCopilot{
+		Enabled:           true,
+		AutoScalingGroups: []string{"web", "missing-workers"},
+		EKSNodeGroups:     []ComputeCopilotNodeGroup{},
+		SpotPercentage:    60,
+		Status:            computeCopilotStatusFailed,
+		StatusMessage:     "Auto Scaling Group missing-workers was not found",
+	}
+	r, s := newTestResource(t, fake, &computeCopilotResource{})
+
+	// Imported settings only have their identifier in state.
+	state := nullResourceState(s)
+	state.SetAttribute(ctx, path.Root("id"), int64(1))
+	resp := fwresource.ReadResponse{State: state}
+	r.Read(ctx, fwresource.ReadRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error reading Compute Copilot: %v", resp.Diagnostics)
+	}
+
+	var model computeCopilotModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &model)...)
+	if !model.AutoScalingGroups.Equal(testAutoScalingGroups(t, "web", "missing-workers")) || !model.EKSNodeGroups.IsNull() {
+		t.Errorf("expected the imported targets to be refreshed, got %s and %s", model.AutoScalingGroups, model.EKSNodeGroups)
+	}
+	if model.ProjectID.ValueInt64() != 1 || model.SpotPercentage.ValueInt64() != 60 || !model.Enabled.ValueBool() {
+		t.Errorf("expected the imported settings to be refreshed, got %+v", model)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("expected a warning about the failed targets, got %v", resp.Diagnostics)
+	}
+
+	delete(fake.computeCopilots, 1)
+	r.Read(ctx, fwresource.ReadRequest{State: resp.State}, &resp)
+	if !resp.State.Raw.IsNull() {
+		t.Errorf("expected the settings deleted in nOps to be removed from state")
+	}
+}
@@ -0,0 +1,372 @@
+package nops
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &costAllocationTagsResource{}
+	_ resource.ResourceWithConfigure      = &costAllocationTagsResource{}
+	_ resource.ResourceWithImportState    = &costAllocationTagsResource{}
+	_ resource.ResourceWithValidateConfig = &costAllocationTagsResource{}
+)
+
+// Statuses of the cost allocation tag keys reported by nOps.
+const (
+	costAllocationTagActive   = "active"
+	costAllocationTagInactive = "inactive"
+	// costAllocationTagFailed is reported for keys AWS refused to activate, usually because they aren't in the billing data yet.
+	costAllocationTagFailed = "failed"
+)
+
+// costAllocationTagsResource is the resource implementation.
+type costAllocationTagsResource struct {
+	client *Client
+}
+
+type costAllocationTagsModel struct {
+	ID            types.Int64  `tfsdk:"id"`
+	LastUpdated   types.String `tfsdk:"last_updated"`
+	ProjectID     types.Int64  `tfsdk:"project_id"`
+	TagKeys       types.Set    `tfsdk:"tag_keys"`
+	TagStatuses   types.Map    `tfsdk:"tag_statuses"`
+	ActiveTagKeys types.Set    `tfsdk:"active_tag_keys"`
+}
+
+// NewCostAllocationTagsResource is a helper function to simplify the provider implementation.
+func NewCostAllocationTagsResource() resource.Resource {
+	return &costAllocationTagsResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *costAllocationTagsResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *costAllocationTagsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cost_allocation_tags"
+}
+
+// Schema defines the schema for the resource.
+func (r *costAllocationTagsResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Declares the cost allocation tag keys nOps activates for a payer project through its integration role. " +
+			"Keys deactivated outside of Terraform, or whose status nOps doesn't report, are reported as drift and activated again on the next apply, " +
+			"destroying the resource deactivates them. A payer has a single set of keys, keys nOps already manages must be imported instead of created.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Identifier of the cost allocation tags, the nOps project identifier",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the resource was last updated, as reported by nOps",
+			},
+			"project_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "nOps project identifier of the payer account, usually `nops_project.id`. The provider refuses linked accounts only when nOps reports their master payer account, it can't tell the others apart from payers",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"tag_keys": schema.SetAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				Description: "Tag keys nOps activates as cost allocation tags, such as `team` or `cost-center`",
+			},
+			"tag_statuses": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Status of each key of `tag_keys` reported by nOps, `active`, `inactive` or `failed` when AWS refused to activate it",
+			},
+			"active_tag_keys": schema.SetAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Every user-defined tag key active in the payer account, including the keys activated outside of nOps",
+			},
+		},
+	}
+}
+
+// ValidateConfig validates the tag keys follow the AWS tagging rules.
+func (r *costAllocationTagsResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config costAllocationTagsModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.TagKeys.IsNull() || config.TagKeys.IsUnknown() {
+		return
+	}
+
+	var keys []types.String
+	resp.Diagnostics.Append(config.TagKeys.ElementsAs(ctx, &keys, false)...)
+	if len(keys) == 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("tag_keys"), "Missing cost allocation tags", "At least one tag key must be set")
+	}
+	for _, key := range keys {
+		if key.IsUnknown() {
+			continue
+		}
+		if length := utf8.RuneCountInString(key.ValueString()); length == 0 || length > 128 || strings.TrimSpace(key.ValueString()) != key.ValueString() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("tag_keys"),
+				"Invalid cost allocation tag",
+				fmt.Sprintf("%q is not a valid tag key, it must be 1 to 128 characters without leading or trailing spaces", key.ValueString()),
+			)
+		}
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *costAllocationTagsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan costAllocationTagsModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Cost allocation tags are activated in the payer account and apply to the whole organization. Linked accounts are
+	// only caught here when nOps reports their master payer account.
+	projects, err := r.client.GetProjects(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote project data",
+			err.Error(),
+		)
+		return
+	}
+	if _, err := lookupPayerProjectByID(projects, plan.ProjectID.ValueInt64()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("project_id"), "Invalid nOps project", err.Error())
+		return
+	}
+
+	// The keys are a singleton of the payer, don't take over the ones nOps already manages for another resource.
+	_, err = r.client.GetCostAllocationTags(ctx, plan.ProjectID.ValueInt64())
+	if err == nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("project_id"),
+			"Error: cost allocation tags are already managed for this project",
+			fmt.Sprintf("Project %d already has cost allocation tags managed by nOps, please import them with the project ID %d instead of creating them", plan.ProjectID.ValueInt64(), plan.ProjectID.ValueInt64()),
+		)
+		return
+	}
+	if !IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error getting remote cost allocation tags",
+			err.Error(),
+		)
+		return
+	}
+
+	r.put(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *costAllocationTagsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state costAllocationTagsModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tags, err := r.client.GetCostAllocationTags(ctx, state.ID.ValueInt64())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, fmt.Sprintf("No cost allocation tags managed by nOps for project %d, removing them from state", state.ID.ValueInt64()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote cost allocation tags",
+			err.Error(),
+		)
+		return
+	}
+
+	// Keys deactivated outside of Terraform are dropped so the next plan activates them again.
+	var drift driftDetector
+	priorStatuses := state.TagStatuses.Elements()
+	statuses := costAllocationTagStatuses(tags)
+	keys := []string{}
+	for _, key := range tags.TagKeys {
+		if statuses[key] != costAllocationTagInactive {
+			keys = append(keys, key)
+			continue
+		}
+		if prior, ok := priorStatuses[key].(types.String); ok {
+			drift.refresh(fmt.Sprintf("tag_statuses[%q]", key), prior, statuses[key])
+		}
+	}
+	drift.report(ctx, &resp.Diagnostics, r.client.DriftReport, fmt.Sprintf("nOps cost allocation tags of project %d", state.ID.ValueInt64()), "", tags.UpdatedAt)
+
+	state.ProjectID = state.ID
+	var d diag.Diagnostics
+	state.TagKeys, d = types.SetValueFrom(ctx, types.StringType, keys)
+	resp.Diagnostics.Append(d...)
+	resp.Diagnostics.Append(state.refresh(ctx, tags)...)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *costAllocationTagsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The tags are imported with the identifier of their payer project.
+	val, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Error parsing ID for import, please check for a correct project ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), val)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *costAllocationTagsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan costAllocationTagsModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.put(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *costAllocationTagsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state costAllocationTagsModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteCostAllocationTags(ctx, state.ID.ValueInt64())
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error deactivating cost allocation tags",
+			err.Error(),
+		)
+		return
+	}
+}
+
+// put sends the planned tag keys to nOps and sets the attributes it computed.
+func (r *costAllocationTagsResource) put(ctx context.Context, plan *costAllocationTagsModel, diags *diag.Diagnostics) {
+	var keys []string
+	diags.Append(plan.TagKeys.ElementsAs(ctx, &keys, false)...)
+	if diags.HasError() {
+		return
+	}
+	sort.Strings(keys)
+
+	tags, err := r.client.PutCostAllocationTags(ctx, plan.ProjectID.ValueInt64(), CostAllocationTags{TagKeys: keys})
+	if err != nil {
+		diags.AddError(
+			"Error activating cost allocation tags",
+			fmt.Sprintf("nOps rejected the cost allocation tags of project %d: %s", plan.ProjectID.ValueInt64(), err.Error()),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("Cost allocation tags %s activated for project %d", strings.Join(keys, ", "), plan.ProjectID.ValueInt64()))
+	plan.ID = plan.ProjectID
+	diags.Append(plan.refresh(ctx, tags)...)
+}
+
+// refresh sets the attributes computed by nOps, warning about the keys AWS refused to activate.
+func (m *costAllocationTagsModel) refresh(ctx context.Context, tags *CostAllocationTags) diag.Diagnostics {
+	var diags diag.Diagnostics
+	statuses := costAllocationTagStatuses(tags)
+	desired := map[string]string{}
+	for _, key := range tags.TagKeys {
+		desired[key] = statuses[key]
+	}
+	active := []string{}
+	for _, tag := range tags.Tags {
+		if tag.Status == costAllocationTagActive {
+			active = append(active, tag.Key)
+		}
+		if tag.Status == costAllocationTagFailed {
+			diags.AddWarning(
+				fmt.Sprintf("AWS refused to activate the cost allocation tag %s", tag.Key),
+				tag.StatusMessage,
+			)
+		}
+	}
+
+	var d diag.Diagnostics
+	m.TagStatuses, d = types.MapValueFrom(ctx, types.StringType, desired)
+	diags.Append(d...)
+	m.ActiveTagKeys, d = types.SetValueFrom(ctx, types.StringType, active)
+	diags.Append(d...)
+	if tags.UpdatedAt != "" {
+		m.LastUpdated = serverTimestamp(tags.UpdatedAt)
+	} else if m.LastUpdated.IsNull() || m.LastUpdated.IsUnknown() {
+		m.LastUpdated = types.StringValue(time.Now().Format(time.RFC3339))
+	}
+
+	return diags
+}
+
+// costAllocationTagStatuses returns the status of each tag key reported by nOps, the managed keys it doesn't
+// report are inactive.
+func costAllocationTagStatuses(tags *CostAllocationTags) map[string]string {
+	statuses := make(map[string]string, len(tags.Tags))
+	for _, key := range tags.TagKeys {
+		statuses[key] = costAllocationTagInactive
+	}
+	for _, tag := range tags.Tags {
+		statuses[tag.Key] = tag.Status
+	}
+
+	return statuses
+}
@@ -0,0 +1,217 @@
+package nops
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testCostAllocationTagsModel(t *testing.T, keys ...string) costAllocationTagsModel {
+	t.Helper()
+
+	return costAllocationTagsModel{
+		ID:            types.Int64Unknown(),
+		LastUpdated:   types.StringUnknown(),
+		ProjectID:     types.Int64Value(1),
+		TagKeys:       testSet(t, types.StringType, keys),
+		TagStatuses:   types.MapUnknown(types.StringType),
+		ActiveTagKeys: types.SetUnknown(types.StringType),
+	}
+}
+
+func TestCostAllocationTagsResourceValidateConfig(t *testing.T) {
+	cases := map[string]struct {
+		keys           []string
+		expectedErrors int
+	}{
+		"valid":          {keys: []string{"team", "cost-center", "aws:createdBy"}},
+		"no key":         {keys: []string{}, expectedErrors: 1},
+		"empty key":      {keys: []string{""}, expectedErrors: 1},
+		"padded key":     {keys: []string{"team "}, expectedErrors: 1},
+		"long key":       {keys: []string{strings.Repeat("k", 129)}, expectedErrors: 1},
+		"max length key": {keys: []string{strings.Repeat("k", 128)}},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, s := newTestResource(t, newFakeNops(), &costAllocationTagsResource{})
+			config := testCostAllocationTagsModel(t, c.keys...)
+
+			var resp fwresource.ValidateConfigResponse
+			r.ValidateConfig(context.Background(), fwresource.ValidateConfigRequest{Config: tfsdk.Config(testResourceState(t, s, &config))}, &resp)
+			if resp.Diagnostics.ErrorsCount() != c.expectedErrors {
+				t.Errorf("expected %d errors, got %v", c.expectedErrors, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestCostAllocationTagsResourceLifecycle(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(Project{ID: 1, AccountNumber: "580010171808", MasterPayerAccountNumber: "580010171808"})
+	r, s := newTestResource(t, fake, &costAllocationTagsResource{})
+
+	plan := testCostAllocationTagsModel(t, "team", "cost-center")
+	createResp := fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}, &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error activating cost allocation tags: %v", createResp.Diagnostics)
+	}
+
+	var state costAllocationTagsModel
+	createResp.Diagnostics.Append(createResp.State.Get(ctx, &state)...)
+	if state.ID.ValueInt64() != 1 || len(state.TagStatuses.Elements()) != 2 {
+		t.Errorf("expected the statuses of the two keys of project 1, got %s with %s", state.ID, state.TagStatuses)
+	}
+	if !state.ActiveTagKeys.Equal(testSet(t, types.StringType, []string{"owner", "team", "cost-center"})) {
+		t.Errorf("expected the keys of the account to be active, got %s", state.ActiveTagKeys)
+	}
+
+	// The team key is deactivated in the billing console, Read reports it as drift.
+	tags := fake.costTags[1]
+	for i := range tags.Tags {
+		if tags.Tags[i].Key == "team" {
+			tags.Tags[i].Status = costAllocationTagInactive
+		}
+	}
+	fake.costTags[1] = tags
+	r.client.DriftReport = true
+	readResp := fwresource.ReadResponse{State: createResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: createResp.State}, &readResp)
+	readResp.Diagnostics.Append(readResp.State.Get(ctx, &state)...)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error reading cost allocation tags: %v", readResp.Diagnostics)
+	}
+	if readResp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("expected a drift warning about the deactivated key, got %v", readResp.Diagnostics)
+	}
+	if !state.TagKeys.Equal(testSet(t, types.StringType, []string{"cost-center"})) {
+		t.Errorf("expected the deactivated key to be dropped, got %s", state.TagKeys)
+	}
+	if status := state.TagStatuses.Elements()["team"]; !status.Equal(types.StringValue(costAllocationTagInactive)) {
+		t.Errorf("expected the team key to be reported inactive, got %s", status)
+	}
+
+	// Applying the configuration again activates it.
+	plan = state
+	plan.TagKeys = testSet(t, types.StringType, []string{"team", "cost-center"})
+	updateResp := fwresource.UpdateResponse{State: readResp.State}
+	r.Update(ctx, fwresource.UpdateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan)), State: readResp.State}, &updateResp)
+	updateResp.Diagnostics.Append(updateResp.State.Get(ctx, &state)...)
+	if updateResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error updating cost allocation tags: %v", updateResp.Diagnostics)
+	}
+	if status := state.TagStatuses.Elements()["team"]; !status.Equal(types.StringValue(costAllocationTagActive)) {
+		t.Errorf("expected the team key to be activated again, got %s", status)
+	}
+
+	deleteResp := fwresource.DeleteResponse{State: updateResp.State}
+	r.Delete(ctx, fwresource.DeleteRequest{State: updateResp.State}, &deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error deactivating cost allocation tags: %v", deleteResp.Diagnostics)
+	}
+	if len(fake.costTags) != 0 {
+		t.Errorf("expected the tags to be deactivated in nOps")
+	}
+}
+
+func TestCostAllocationTagsResourceCreateFailedKey(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(Project{ID: 1, AccountNumber: "580010171808"})
+	r, s := newTestResource(t, fake, &costAllocationTagsResource{})
+
+	plan := testCostAllocationTagsModel(t, "team", "missing-key")
+	resp := fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error activating cost allocation tags: %v", resp.Diagnostics)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("expected a warning about the key AWS refused, got %v", resp.Diagnostics)
+	}
+
+	// Keys that failed to activate are kept, nOps retries them.
+	var state costAllocationTagsModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if !state.TagKeys.Equal(plan.TagKeys) {
+		t.Errorf("expected the failed key to be kept, got %s", state.TagKeys)
+	}
+	if status := state.TagStatuses.Elements()["missing-key"]; !status.Equal(types.StringValue(costAllocationTagFailed)) {
+		t.Errorf("expected the missing key to be reported failed, got %s", status)
+	}
+}
+
+func TestCostAllocationTagsResourceReadUnreportedKey(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(Project{ID: 1, AccountNumber: "580010171808"})
+	fake.costTags[1] = CostAllocationTags{
+		TagKeys: []string{"team", "cost-center"},
+		Tags:    []CostAllocationTag{{Key: "team", Status: costAllocationTagActive}},
+	}
+	r, s := newTestResource(t, fake, &costAllocationTagsResource{})
+
+	// Keys nOps doesn't report a status for are inactive, they are dropped like deactivated keys.
+	state := nullResourceState(s)
+	state.SetAttribute(ctx, path.Root("id"), int64(1))
+	resp := fwresource.ReadResponse{State: state}
+	r.Read(ctx, fwresource.ReadRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error reading cost allocation tags: %v", resp.Diagnostics)
+	}
+
+	var model costAllocationTagsModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &model)...)
+	if !model.TagKeys.Equal(testSet(t, types.StringType, []string{"team"})) {
+		t.Errorf("expected the unreported key to be dropped, got %s", model.TagKeys)
+	}
+	if status := model.TagStatuses.Elements()["cost-center"]; !status.Equal(types.StringValue(costAllocationTagInactive)) {
+		t.Errorf("expected the unreported key to be reported inactive, got %s", status)
+	}
+}
+
+func TestCostAllocationTagsResourceCreateLinkedAccount(t *testing.T) {
+	fake := newFakeNops(Project{ID: 1, AccountNumber: "580010171808", MasterPayerAccountNumber: "123456789012"})
+	r, s := newTestResource(t, fake, &costAllocationTagsResource{})
+
+	plan := testCostAllocationTagsModel(t, "team")
+	resp := fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(context.Background(), fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("expected an error activating the tags of a linked account")
+	}
+}
+
+func TestCostAllocationTagsResourceImport(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(Project{ID: 1, AccountNumber: "580010171808"})
+	fake.costTags[1] = CostAllocationTags{
+		TagKeys: []string{"team"},
+		Tags:    []CostAllocationTag{{Key: "team", Status: costAllocationTagActive}},
+	}
+	r, s := newTestResource(t, fake, &costAllocationTagsResource{})
+
+	state := nullResourceState(s)
+	state.SetAttribute(ctx, path.Root("id"), int64(1))
+	resp := fwresource.ReadResponse{State: state}
+	r.Read(ctx, fwresource.ReadRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error reading cost allocation tags: %v", resp.Diagnostics)
+	}
+
+	var model costAllocationTagsModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &model)...)
+	if model.ProjectID.ValueInt64() != 1 || !model.TagKeys.Equal(testSet(t, types.StringType, []string{"team"})) || model.LastUpdated.IsNull() {
+		t.Errorf("expected the imported tags to be refreshed, got %+v", model)
+	}
+
+	delete(fake.costTags, 1)
+	r.Read(ctx, fwresource.ReadRequest{State: resp.State}, &resp)
+	if !resp.State.Raw.IsNull() {
+		t.Errorf("expected the tags deleted in nOps to be removed from state")
+	}
+}
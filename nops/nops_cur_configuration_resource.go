@@ -0,0 +1,381 @@
+package nops
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &curConfigurationResource{}
+	_ resource.ResourceWithConfigure      = &curConfigurationResource{}
+	_ resource.ResourceWithImportState    = &curConfigurationResource{}
+	_ resource.ResourceWithValidateConfig = &curConfigurationResource{}
+)
+
+// Cost report formats supported by nOps.
+const (
+	curFormatLegacy = "cur_legacy"
+	curFormatCUR2   = "cur_2_0"
+	curFormatFOCUS  = "focus"
+)
+
+// curStatusFailed is the ingestion status reported by nOps when the reports can't be read.
+const curStatusFailed = "failed"
+
+var (
+	// bucketNamePattern matches the S3 bucket naming rules.
+	bucketNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+	// legacyReportNamePattern matches the names allowed for legacy Cost and Usage Reports.
+	legacyReportNamePattern = regexp.MustCompile(`^[0-9A-Za-z!\-_.*'()]{1,256}$`)
+	// exportNamePattern matches the names allowed for Data Exports, used by CUR 2.0 and FOCUS.
+	exportNamePattern = regexp.MustCompile(`^[0-9A-Za-z\-_]{1,128}$`)
+)
+
+// curConfigurationResource is the resource implementation.
+type curConfigurationResource struct {
+	client *Client
+}
+
+type curConfigurationModel struct {
+	ID             types.Int64  `tfsdk:"id"`
+	LastUpdated    types.String `tfsdk:"last_updated"`
+	ProjectID      types.Int64  `tfsdk:"project_id"`
+	BucketName     types.String `tfsdk:"bucket_name"`
+	Prefix         types.String `tfsdk:"prefix"`
+	ReportName     types.String `tfsdk:"report_name"`
+	Format         types.String `tfsdk:"format"`
+	Status         types.String `tfsdk:"status"`
+	StatusMessage  types.String `tfsdk:"status_message"`
+	LastIngestedAt types.String `tfsdk:"last_ingested_at"`
+}
+
+// NewCURConfigurationResource is a helper function to simplify the provider implementation.
+func NewCURConfigurationResource() resource.Resource {
+	return &curConfigurationResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *curConfigurationResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *curConfigurationResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cur_configuration"
+}
+
+// Schema defines the schema for the resource.
+func (r *curConfigurationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Configures the Cost and Usage Report, or the CUR 2.0 and FOCUS Data Export, nOps ingests for a payer project. " +
+			"nOps validates the configuration before accepting it and reports the ingestion status of the reports. " +
+			"A project has a single configuration, one that already exists in nOps must be imported instead of created.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Identifier of the configuration, the nOps project identifier",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the resource was last updated, as reported by nOps",
+			},
+			"project_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "nOps project identifier of the payer account delivering the reports, usually `nops_project.id`",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"bucket_name": schema.StringAttribute{
+				Required:    true,
+				Description: "AWS S3 bucket the reports are delivered to, usually the nOps system bucket",
+			},
+			"prefix": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+				Description: "S3 prefix of the reports in the bucket, without leading or trailing slashes. Defaults to the bucket root",
+			},
+			"report_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the Cost and Usage Report, or of the Data Export for the CUR 2.0 and FOCUS formats",
+			},
+			"format": schema.StringAttribute{
+				Required:    true,
+				Description: "Format of the reports, `cur_legacy` for a legacy Cost and Usage Report, `cur_2_0` or `focus` for a Data Export",
+			},
+			"status": schema.StringAttribute{
+				Computed:    true,
+				Description: "Ingestion status of the reports reported by nOps, such as `pending`, `ingesting`, `active` or `failed`",
+			},
+			"status_message": schema.StringAttribute{
+				Computed:    true,
+				Description: "Details about the ingestion status, explaining the failure when `status` is `failed`",
+			},
+			"last_ingested_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when nOps last ingested a report, empty until the first report is ingested",
+			},
+		},
+	}
+}
+
+// ValidateConfig validates the report names and locations follow the AWS rules of the format.
+func (r *curConfigurationResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config curConfigurationModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.BucketName.IsNull() && !config.BucketName.IsUnknown() && !bucketNamePattern.MatchString(config.BucketName.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("bucket_name"),
+			"Invalid bucket name",
+			fmt.Sprintf("%q is not a valid S3 bucket name, it must be 3 to 63 lowercase letters, numbers, dots or hyphens", config.BucketName.ValueString()),
+		)
+	}
+
+	if prefix := config.Prefix.ValueString(); strings.HasPrefix(prefix, "/") || strings.HasSuffix(prefix, "/") {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("prefix"),
+			"Invalid report prefix",
+			fmt.Sprintf("%q must not start or end with a slash", prefix),
+		)
+	}
+
+	if config.Format.IsUnknown() || config.Format.IsNull() {
+		return
+	}
+	var reportNamePattern *regexp.Regexp
+	switch config.Format.ValueString() {
+	case curFormatLegacy:
+		reportNamePattern = legacyReportNamePattern
+	case curFormatCUR2, curFormatFOCUS:
+		reportNamePattern = exportNamePattern
+	default:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("format"),
+			"Invalid report format",
+			fmt.Sprintf("%q is not supported, expected one of %s, %s or %s", config.Format.ValueString(), curFormatLegacy, curFormatCUR2, curFormatFOCUS),
+		)
+		return
+	}
+
+	if !config.ReportName.IsNull() && !config.ReportName.IsUnknown() && !reportNamePattern.MatchString(config.ReportName.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("report_name"),
+			"Invalid report name",
+			fmt.Sprintf("%q is not a valid report name for the %s format, expected it to match %s", config.ReportName.ValueString(), config.Format.ValueString(), reportNamePattern),
+		)
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *curConfigurationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan curConfigurationModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Cost reports are exported by the payer account and cover its linked accounts. Linked accounts are
+	// only caught here when nOps reports their master payer account.
+	projects, err := r.client.GetProjects(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote project data",
+			err.Error(),
+		)
+		return
+	}
+	if _, err := lookupPayerProjectByID(projects, plan.ProjectID.ValueInt64()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("project_id"), "Invalid nOps project", err.Error())
+		return
+	}
+
+	// The configuration is a singleton of the project, don't overwrite one set up outside of this resource.
+	_, err = r.client.GetCURConfiguration(ctx, plan.ProjectID.ValueInt64())
+	if err == nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("project_id"),
+			"Error: cost reports are already configured for this project",
+			fmt.Sprintf("Project %d already has a cost report configuration in nOps, please import it with the project ID %d instead of creating it", plan.ProjectID.ValueInt64(), plan.ProjectID.ValueInt64()),
+		)
+		return
+	}
+	if !IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error getting remote cost report configuration",
+			err.Error(),
+		)
+		return
+	}
+
+	configuration, err := r.client.PutCURConfiguration(ctx, plan.ProjectID.ValueInt64(), plan.configuration())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error configuring cost reports",
+			fmt.Sprintf("nOps rejected the cost report configuration of project %d: %s", plan.ProjectID.ValueInt64(), err.Error()),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("Cost reports configured for project %d with status %s", plan.ProjectID.ValueInt64(), configuration.Status))
+	plan.ID = plan.ProjectID
+	plan.refresh(configuration)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *curConfigurationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state curConfigurationModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	configuration, err := r.client.GetCURConfiguration(ctx, state.ID.ValueInt64())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, fmt.Sprintf("No cost report configuration found in nOps for project %d, removing it from state", state.ID.ValueInt64()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote cost report configuration",
+			err.Error(),
+		)
+		return
+	}
+
+	state.ProjectID = state.ID
+	state.BucketName = types.StringValue(configuration.BucketName)
+	state.Prefix = types.StringValue(configuration.Prefix)
+	state.ReportName = types.StringValue(configuration.ReportName)
+	state.Format = types.StringValue(configuration.Format)
+	state.refresh(configuration)
+	if configuration.Status == curStatusFailed {
+		resp.Diagnostics.AddWarning(
+			fmt.Sprintf("nOps can't ingest the cost reports of project %d", state.ID.ValueInt64()),
+			configuration.StatusMessage,
+		)
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *curConfigurationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The configuration is imported with the identifier of its project.
+	val, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Error parsing ID for import, please check for a correct project ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), val)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *curConfigurationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan curConfigurationModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	configuration, err := r.client.PutCURConfiguration(ctx, plan.ProjectID.ValueInt64(), plan.configuration())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating cost report configuration",
+			fmt.Sprintf("nOps rejected the cost report configuration of project %d: %s", plan.ProjectID.ValueInt64(), err.Error()),
+		)
+		return
+	}
+
+	plan.ID = plan.ProjectID
+	plan.refresh(configuration)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *curConfigurationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state curConfigurationModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteCURConfiguration(ctx, state.ID.ValueInt64())
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error deleting cost report configuration",
+			err.Error(),
+		)
+		return
+	}
+}
+
+// configuration returns the nOps payload for the planned configuration.
+func (m *curConfigurationModel) configuration() CURConfiguration {
+	return CURConfiguration{
+		BucketName: m.BucketName.ValueString(),
+		Prefix:     m.Prefix.ValueString(),
+		ReportName: m.ReportName.ValueString(),
+		Format:     m.Format.ValueString(),
+	}
+}
+
+// refresh sets the attributes computed by nOps.
+func (m *curConfigurationModel) refresh(configuration *CURConfiguration) {
+	m.Status = types.StringValue(configuration.Status)
+	m.StatusMessage = types.StringValue(configuration.StatusMessage)
+	m.LastIngestedAt = serverTimestamp(configuration.LastIngestedAt)
+	if configuration.UpdatedAt != "" {
+		m.LastUpdated = serverTimestamp(configuration.UpdatedAt)
+	} else if m.LastUpdated.IsNull() || m.LastUpdated.IsUnknown() {
+		m.LastUpdated = types.StringValue(time.Now().Format(time.RFC3339))
+	}
+}
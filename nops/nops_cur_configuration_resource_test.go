@@ -0,0 +1,137 @@
+package nops
+
+import (
+	"context"
+	"testing"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testCURConfigurationModel() curConfigurationModel {
+	return curConfigurationModel{
+		ID:             types.Int64Unknown(),
+		LastUpdated:    types.StringUnknown(),
+		ProjectID:      types.Int64Value(1),
+		BucketName:     types.StringValue("nops-1000-1-580010171808"),
+		Prefix:         types.StringValue("reports"),
+		ReportName:     types.StringValue("nops-cur-2"),
+		Format:         types.StringValue(curFormatCUR2),
+		Status:         types.StringUnknown(),
+		StatusMessage:  types.StringUnknown(),
+		LastIngestedAt: types.StringUnknown(),
+	}
+}
+
+func TestCURConfigurationResourceValidateConfig(t *testing.T) {
+	cases := map[string]struct {
+		update         func(*curConfigurationModel)
+		expectedErrors int
+	}{
+		"valid": {update: func(m *curConfigurationModel) {}},
+		"legacy report name": {update: func(m *curConfigurationModel) {
+			m.Format, m.ReportName = types.StringValue(curFormatLegacy), types.StringValue("nops.cur(legacy)")
+		}},
+		"export report name":   {update: func(m *curConfigurationModel) { m.ReportName = types.StringValue("nops.cur") }, expectedErrors: 1},
+		"unknown format":       {update: func(m *curConfigurationModel) { m.Format = types.StringValue("csv") }, expectedErrors: 1},
+		"invalid bucket":       {update: func(m *curConfigurationModel) { m.BucketName = types.StringValue("Nops_Bucket") }, expectedErrors: 1},
+		"slash prefix":         {update: func(m *curConfigurationModel) { m.Prefix = types.StringValue("/reports/") }, expectedErrors: 1},
+		"unknown bucket value": {update: func(m *curConfigurationModel) { m.BucketName = types.StringUnknown() }},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, s := newTestResource(t, newFakeNops(), &curConfigurationResource{})
+			config := testCURConfigurationModel()
+			c.update(&config)
+
+			var resp fwresource.ValidateConfigResponse
+			r.ValidateConfig(context.Background(), fwresource.ValidateConfigRequest{Config: tfsdk.Config(testResourceState(t, s, &config))}, &resp)
+			if resp.Diagnostics.ErrorsCount() != c.expectedErrors {
+				t.Errorf("expected %d errors, got %v", c.expectedErrors, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestCURConfigurationResourceCreate(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(Project{ID: 1, AccountNumber: "580010171808"})
+	r, s := newTestResource(t, fake, &curConfigurationResource{})
+
+	plan := testCURConfigurationModel()
+	resp := fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error creating configuration: %v", resp.Diagnostics)
+	}
+
+	var state curConfigurationModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if state.ID.ValueInt64() != 1 || state.Status.ValueString() != "active" {
+		t.Errorf("expected an active configuration for project 1, got %s with status %s", state.ID, state.Status)
+	}
+	if state.LastIngestedAt.ValueString() != "2024-10-21T15:04:05Z" || state.LastUpdated.ValueString() != "2024-10-21T15:04:05Z" {
+		t.Errorf("expected timestamps reported by nOps, got %s and %s", state.LastIngestedAt, state.LastUpdated)
+	}
+	if !fake.requested("PUT", "/c/admin/projectaws/1/cur_configuration/") {
+		t.Errorf("expected the configuration to be sent to nOps")
+	}
+}
+
+func TestCURConfigurationResourceCreateUnknownProject(t *testing.T) {
+	r, s := newTestResource(t, newFakeNops(), &curConfigurationResource{})
+
+	plan := testCURConfigurationModel()
+	resp := fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(context.Background(), fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("expected an error configuring the reports of a project that doesn't exist")
+	}
+}
+
+func TestCURConfigurationResourceCreateLinkedAccount(t *testing.T) {
+	fake := newFakeNops(Project{ID: 1, AccountNumber: "580010171808", MasterPayerAccountNumber: "123456789012"})
+	r, s := newTestResource(t, fake, &curConfigurationResource{})
+
+	plan := testCURConfigurationModel()
+	resp := fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(context.Background(), fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("expected an error configuring the reports of a linked account")
+	}
+	if fake.requested("PUT", "/c/admin/projectaws/1/cur_configuration/") {
+		t.Errorf("expected no configuration to be sent to nOps")
+	}
+}
+
+func TestCURConfigurationResourceReadFailed(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(Project{ID: 1, AccountNumber: "580010171808"})
+	fake.curConfigurations[1] = CURConfiguration{
+		BucketName:    "missing-bucket",
+		ReportName:    "nops-cur-2",
+		Format:        curFormatCUR2,
+		Status:        curStatusFailed,
+		StatusMessage: "Access denied reading s3://missing-bucket",
+	}
+	r, s := newTestResource(t, fake, &curConfigurationResource{})
+
+	model := testCURConfigurationModel()
+	model.ID = types.Int64Value(1)
+	resp := fwresource.ReadResponse{State: testResourceState(t, s, &model)}
+	r.Read(ctx, fwresource.ReadRequest{State: resp.State}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error reading configuration: %v", resp.Diagnostics)
+	}
+
+	var state curConfigurationModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if state.BucketName.ValueString() != "missing-bucket" || state.Prefix.ValueString() != "" || state.Status.ValueString() != curStatusFailed {
+		t.Errorf("expected the configuration to be refreshed, got %s/%s with status %s", state.BucketName, state.Prefix, state.Status)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("expected a warning about the failed ingestion, got %v", resp.Diagnostics)
+	}
+}
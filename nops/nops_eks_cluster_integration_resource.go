@@ -0,0 +1,382 @@
+package nops
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &eksClusterIntegrationResource{}
+	_ resource.ResourceWithConfigure      = &eksClusterIntegrationResource{}
+	_ resource.ResourceWithImportState    = &eksClusterIntegrationResource{}
+	_ resource.ResourceWithValidateConfig = &eksClusterIntegrationResource{}
+)
+
+// Heartbeat statuses of the cost agent reported by nOps.
+const (
+	eksAgentStatusPending      = "pending"
+	eksAgentStatusConnected    = "connected"
+	eksAgentStatusDisconnected = "disconnected"
+)
+
+var (
+	// awsRegionPattern matches the AWS region names, such as us-east-1 or us-gov-west-1.
+	awsRegionPattern = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]?)?-[a-z]+-\d$`)
+	// eksClusterNamePattern matches the EKS cluster naming rules.
+	eksClusterNamePattern = regexp.MustCompile(`^[0-9A-Za-z][A-Za-z0-9\-_]{0,99}$`)
+)
+
+// eksClusterIntegrationResource is the resource implementation.
+type eksClusterIntegrationResource struct {
+	client *Client
+}
+
+type eksClusterIntegrationModel struct {
+	ID              types.Int64  `tfsdk:"id"`
+	LastUpdated     types.String `tfsdk:"last_updated"`
+	ProjectID       types.Int64  `tfsdk:"project_id"`
+	AccountID       types.String `tfsdk:"account_id"`
+	Region          types.String `tfsdk:"region"`
+	ClusterName     types.String `tfsdk:"cluster_name"`
+	AgentToken      types.String `tfsdk:"agent_token"`
+	HelmValues      types.String `tfsdk:"helm_values"`
+	AgentStatus     types.String `tfsdk:"agent_status"`
+	AgentVersion    types.String `tfsdk:"agent_version"`
+	LastHeartbeatAt types.String `tfsdk:"last_heartbeat_at"`
+}
+
+// NewEKSClusterIntegrationResource is a helper function to simplify the provider implementation.
+func NewEKSClusterIntegrationResource() resource.Resource {
+	return &eksClusterIntegrationResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *eksClusterIntegrationResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *eksClusterIntegrationResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_eks_cluster_integration"
+}
+
+// Schema defines the schema for the resource.
+func (r *eksClusterIntegrationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Registers an EKS cluster with a nOps project for Kubernetes cost visibility. nOps returns the token and Helm values " +
+			"the cost agent is installed with, and reports the agent heartbeat once it runs in the cluster.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "nOps identifier of the registered cluster",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the resource was last updated, as reported by nOps",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"project_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "nOps project identifier of the AWS account running the cluster, usually `nops_project.id`",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"account_id": schema.StringAttribute{
+				Required:    true,
+				Description: "AWS account running the cluster, it must be the account of the project",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"region": schema.StringAttribute{
+				Required:    true,
+				Description: "AWS region of the cluster",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"cluster_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the EKS cluster",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"agent_token": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Token the cost agent authenticates to nOps with",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"helm_values": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "YAML values of the nOps cost agent Helm chart for the cluster, including `agent_token`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"agent_status": schema.StringAttribute{
+				Computed:    true,
+				Description: "Heartbeat status of the cost agent, `pending` until the agent first reports, then `connected` or `disconnected`",
+			},
+			"agent_version": schema.StringAttribute{
+				Computed:    true,
+				Description: "Version of the cost agent running in the cluster, empty until the agent first reports",
+			},
+			"last_heartbeat_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp of the last heartbeat of the cost agent, null until the agent first reports",
+			},
+		},
+	}
+}
+
+// ValidateConfig validates the account, region and cluster names follow the AWS rules.
+func (r *eksClusterIntegrationResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config eksClusterIntegrationModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for attribute, check := range map[string]struct {
+		value   types.String
+		pattern *regexp.Regexp
+	}{
+		"account_id":   {config.AccountID, accountIDPattern},
+		"region":       {config.Region, awsRegionPattern},
+		"cluster_name": {config.ClusterName, eksClusterNamePattern},
+	} {
+		if !check.value.IsNull() && !check.value.IsUnknown() && !check.pattern.MatchString(check.value.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attribute),
+				"Invalid EKS cluster",
+				fmt.Sprintf("%q is not a valid %s, expected it to match %s", check.value.ValueString(), attribute, check.pattern),
+			)
+		}
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *eksClusterIntegrationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan eksClusterIntegrationModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Clusters can only be registered with the project of the account running them.
+	projects, err := r.client.GetProjects(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote project data",
+			err.Error(),
+		)
+		return
+	}
+	project := lookupProject(projects, plan.ProjectID.ValueInt64())
+	if project == nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("project_id"),
+			"Invalid nOps project",
+			fmt.Sprintf("No project with ID %d exists in nOps", plan.ProjectID.ValueInt64()),
+		)
+		return
+	}
+	if project.AccountNumber != plan.AccountID.ValueString() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("account_id"),
+			"Invalid EKS cluster account",
+			fmt.Sprintf("nOps project %d is for account %s, register the cluster with the project of account %s", project.ID, project.AccountNumber, plan.AccountID.ValueString()),
+		)
+		return
+	}
+
+	cluster, err := r.client.CreateEKSCluster(ctx, plan.ProjectID.ValueInt64(), NewEKSCluster{
+		ClusterName:   plan.ClusterName.ValueString(),
+		Region:        plan.Region.ValueString(),
+		AccountNumber: plan.AccountID.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error registering EKS cluster",
+			fmt.Sprintf("Could not register cluster %s in %s with project %d: %s", plan.ClusterName.ValueString(), plan.Region.ValueString(), plan.ProjectID.ValueInt64(), err.Error()),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("EKS cluster %s registered with project %d as %d", cluster.ClusterName, plan.ProjectID.ValueInt64(), cluster.ID))
+	plan.refresh(cluster)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *eksClusterIntegrationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state eksClusterIntegrationModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cluster, err := r.client.GetEKSCluster(ctx, state.ProjectID.ValueInt64(), state.ID.ValueInt64())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, fmt.Sprintf("EKS cluster %d isn't registered in nOps anymore, removing it from state", state.ID.ValueInt64()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote EKS cluster data",
+			err.Error(),
+		)
+		return
+	}
+
+	state.AccountID = types.StringValue(cluster.AccountNumber)
+	state.Region = types.StringValue(cluster.Region)
+	state.ClusterName = types.StringValue(cluster.ClusterName)
+	state.refresh(cluster)
+	if cluster.AgentStatus == eksAgentStatusDisconnected {
+		resp.Diagnostics.AddWarning(
+			fmt.Sprintf("The nOps cost agent of EKS cluster %s stopped reporting", cluster.ClusterName),
+			fmt.Sprintf("The last heartbeat was received at %s, check the agent pods are running in the cluster.", state.LastHeartbeatAt.ValueString()),
+		)
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *eksClusterIntegrationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Clusters are imported with the identifiers of their project and of the cluster, <project_id>/<id>.
+	project, cluster, err := parseProjectChildID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error parsing ID for import, please check for a correct <project_id>/<id> identifier",
+			fmt.Sprintf("Could not parse %q: %s", req.ID, err.Error()),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), project)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), cluster)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *eksClusterIntegrationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Every argument replaces the cluster registration, only the computed attributes are kept.
+	var plan eksClusterIntegrationModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cluster, err := r.client.GetEKSCluster(ctx, plan.ProjectID.ValueInt64(), plan.ID.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote EKS cluster data",
+			err.Error(),
+		)
+		return
+	}
+	plan.refresh(cluster)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *eksClusterIntegrationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state eksClusterIntegrationModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteEKSCluster(ctx, state.ProjectID.ValueInt64(), state.ID.ValueInt64())
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error unregistering EKS cluster",
+			err.Error(),
+		)
+		return
+	}
+}
+
+// refresh sets the attributes computed by nOps.
+func (m *eksClusterIntegrationModel) refresh(cluster *EKSCluster) {
+	m.ID = types.Int64Value(int64(cluster.ID))
+	m.AgentToken = types.StringValue(cluster.AgentToken)
+	m.HelmValues = types.StringValue(cluster.HelmValues)
+	m.AgentStatus = types.StringValue(cluster.AgentStatus)
+	m.AgentVersion = types.StringValue(cluster.AgentVersion)
+	m.LastHeartbeatAt = serverTimestamp(cluster.LastHeartbeatAt)
+	m.LastUpdated = serverTimestamp(cluster.UpdatedAt)
+	if m.LastUpdated.IsNull() {
+		m.LastUpdated = types.StringValue(time.Now().Format(time.RFC3339))
+	}
+}
+
+// parseProjectChildID parses the <project_id>/<id> import identifier of an object nested in a project.
+func parseProjectChildID(id string) (int64, int64, error) {
+	projectID, childID, found := strings.Cut(id, "/")
+	if !found {
+		return 0, 0, fmt.Errorf("expected <project_id>/<id>")
+	}
+	project, err := strconv.ParseInt(projectID, 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	child, err := strconv.ParseInt(childID, 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return project, child, nil
+}
@@ -0,0 +1,184 @@
+package nops
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testEKSClusterIntegrationModel() eksClusterIntegrationModel {
+	return eksClusterIntegrationModel{
+		ID:              types.Int64Unknown(),
+		LastUpdated:     types.StringUnknown(),
+		ProjectID:       types.Int64Value(1),
+		AccountID:       types.StringValue("580010171808"),
+		Region:          types.StringValue("us-east-1"),
+		ClusterName:     types.StringValue("production"),
+		AgentToken:      types.StringUnknown(),
+		HelmValues:      types.StringUnknown(),
+		AgentStatus:     types.StringUnknown(),
+		AgentVersion:    types.StringUnknown(),
+		LastHeartbeatAt: types.StringUnknown(),
+	}
+}
+
+func TestEKSClusterIntegrationResourceValidateConfig(t *testing.T) {
+	cases := map[string]struct {
+		update         func(*eksClusterIntegrationModel)
+		expectedErrors int
+	}{
+		"valid":             {update: func(m *eksClusterIntegrationModel) {}},
+		"gov region":        {update: func(m *eksClusterIntegrationModel) { m.Region = types.StringValue("us-gov-west-1") }},
+		"invalid account":   {update: func(m *eksClusterIntegrationModel) { m.AccountID = types.StringValue("58001017180") }, expectedErrors: 1},
+		"invalid region":    {update: func(m *eksClusterIntegrationModel) { m.Region = types.StringValue("us-east-1a") }, expectedErrors: 1},
+		"invalid cluster":   {update: func(m *eksClusterIntegrationModel) { m.ClusterName = types.StringValue("-production") }, expectedErrors: 1},
+		"long cluster name": {update: func(m *eksClusterIntegrationModel) { m.ClusterName = types.StringValue(strings.Repeat("a", 101)) }, expectedErrors: 1},
+		"unknown cluster":   {update: func(m *eksClusterIntegrationModel) { m.ClusterName = types.StringUnknown() }},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, s := newTestResource(t, newFakeNops(), &eksClusterIntegrationResource{})
+			config := testEKSClusterIntegrationModel()
+			c.update(&config)
+
+			var resp fwresource.ValidateConfigResponse
+			r.ValidateConfig(context.Background(), fwresource.ValidateConfigRequest{Config: tfsdk.Config(testResourceState(t, s, &config))}, &resp)
+			if resp.Diagnostics.ErrorsCount() != c.expectedErrors {
+				t.Errorf("expected %d errors, got %v", c.expectedErrors, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestEKSClusterIntegrationResourceLifecycle(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(Project{ID: 1, AccountNumber: "580010171808"})
+	r, s := newTestResource(t, fake, &eksClusterIntegrationResource{})
+
+	plan := testEKSClusterIntegrationModel()
+	createResp := fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}, &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error registering EKS cluster: %v", createResp.Diagnostics)
+	}
+
+	var state eksClusterIntegrationModel
+	createResp.Diagnostics.Append(createResp.State.Get(ctx, &state)...)
+	if state.ID.ValueInt64() != 2 || state.AgentToken.ValueString() != "nops-agent-token-2" {
+		t.Errorf("expected cluster 2 with its agent token, got %s with %s", state.ID, state.AgentToken)
+	}
+	if !strings.Contains(state.HelmValues.ValueString(), "agentToken: nops-agent-token-2") {
+		t.Errorf("expected the Helm values to include the agent token, got %s", state.HelmValues)
+	}
+	if state.AgentStatus.ValueString() != eksAgentStatusPending || !state.LastHeartbeatAt.IsNull() {
+		t.Errorf("expected the agent to be pending, got %s with heartbeat %s", state.AgentStatus, state.LastHeartbeatAt)
+	}
+
+	// The agent reports once it is installed in the cluster.
+	cluster := fake.eksClusters[2]
+	cluster.AgentStatus, cluster.AgentVersion, cluster.LastHeartbeatAt = eksAgentStatusConnected, "1.4.2", fakeNopsTimestamp
+	fake.eksClusters[2] = cluster
+	readResp := fwresource.ReadResponse{State: createResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: createResp.State}, &readResp)
+	readResp.Diagnostics.Append(readResp.State.Get(ctx, &state)...)
+	if readResp.Diagnostics.HasError() || readResp.Diagnostics.WarningsCount() != 0 {
+		t.Fatalf("unexpected diagnostics reading EKS cluster: %v", readResp.Diagnostics)
+	}
+	if state.AgentStatus.ValueString() != eksAgentStatusConnected || state.AgentVersion.ValueString() != "1.4.2" || state.LastHeartbeatAt.ValueString() != "2024-10-21T15:04:05Z" {
+		t.Errorf("expected the agent heartbeat to be refreshed, got %s %s at %s", state.AgentStatus, state.AgentVersion, state.LastHeartbeatAt)
+	}
+
+	deleteResp := fwresource.DeleteResponse{State: readResp.State}
+	r.Delete(ctx, fwresource.DeleteRequest{State: readResp.State}, &deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error unregistering EKS cluster: %v", deleteResp.Diagnostics)
+	}
+	if len(fake.eksClusters) != 0 {
+		t.Errorf("expected the cluster to be unregistered from nOps")
+	}
+}
+
+func TestEKSClusterIntegrationResourceCreateInvalidProject(t *testing.T) {
+	cases := map[string]*fakeNops{
+		"unknown project": newFakeNops(),
+		"other account":   newFakeNops(Project{ID: 1, AccountNumber: "123456789012"}),
+	}
+
+	for name, fake := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, s := newTestResource(t, fake, &eksClusterIntegrationResource{})
+
+			plan := testEKSClusterIntegrationModel()
+			resp := fwresource.CreateResponse{State: nullResourceState(s)}
+			r.Create(context.Background(), fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}, &resp)
+			if !resp.Diagnostics.HasError() {
+				t.Errorf("expected an error registering the cluster")
+			}
+			if len(fake.eksClusters) != 0 {
+				t.Errorf("expected no cluster to be registered in nOps")
+			}
+		})
+	}
+}
+
+func TestEKSClusterIntegrationResourceReadDisconnected(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(Project{ID: 1, AccountNumber: "580010171808"})
+	fake.eksClusters[4] = EKSCluster{
+		ID:              4,
+		ClusterName:     "production",
+		Region:          "eu-west-1",
+		AccountNumber:   "580010171808",
+		AgentToken:      "nops-agent-token-4",
+		HelmValues:      "agentToken: nops-agent-token-4\n",
+		AgentStatus:     eksAgentStatusDisconnected,
+		AgentVersion:    "1.4.2",
+		LastHeartbeatAt: fakeNopsTimestamp,
+	}
+	r, s := newTestResource(t, fake, &eksClusterIntegrationResource{})
+
+	// Imported clusters only have their identifiers in state.
+	importResp := fwresource.ImportStateResponse{State: nullResourceState(s)}
+	r.ImportState(ctx, fwresource.ImportStateRequest{ID: "1/4"}, &importResp)
+	if importResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error importing EKS cluster: %v", importResp.Diagnostics)
+	}
+
+	resp := fwresource.ReadResponse{State: importResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: importResp.State}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error reading EKS cluster: %v", resp.Diagnostics)
+	}
+
+	var state eksClusterIntegrationModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if state.Region.ValueString() != "eu-west-1" || state.AgentToken.ValueString() != "nops-agent-token-4" {
+		t.Errorf("expected the imported cluster to be refreshed, got %+v", state)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("expected a warning about the disconnected agent, got %v", resp.Diagnostics)
+	}
+
+	delete(fake.eksClusters, 4)
+	r.Read(ctx, fwresource.ReadRequest{State: resp.State}, &resp)
+	if resp.Diagnostics.HasError() || !resp.State.Raw.IsNull() {
+		t.Errorf("expected the cluster unregistered in nOps to be removed from state, got %v", resp.Diagnostics)
+	}
+}
+
+func TestEKSClusterIntegrationResourceImportInvalidID(t *testing.T) {
+	r, s := newTestResource(t, newFakeNops(), &eksClusterIntegrationResource{})
+
+	for _, id := range []string{"4", "1/", "project/4"} {
+		resp := fwresource.ImportStateResponse{State: nullResourceState(s)}
+		r.ImportState(context.Background(), fwresource.ImportStateRequest{ID: id}, &resp)
+		if !resp.Diagnostics.HasError() {
+			t.Errorf("expected an error importing %q", id)
+		}
+	}
+}
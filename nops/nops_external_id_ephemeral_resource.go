@@ -0,0 +1,107 @@
+package nops
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ ephemeral.EphemeralResource              = &externalIDEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &externalIDEphemeralResource{}
+)
+
+// externalIDEphemeralResource is the ephemeral resource implementation.
+type externalIDEphemeralResource struct {
+	client *Client
+}
+
+type externalIDEphemeralResourceModel struct {
+	ProjectID  types.Int64  `tfsdk:"project_id"`
+	ExternalID types.String `tfsdk:"external_id"`
+}
+
+// NewExternalIDEphemeralResource is a helper function to simplify the provider implementation.
+func NewExternalIDEphemeralResource() ephemeral.EphemeralResource {
+	return &externalIDEphemeralResource{}
+}
+
+// Configure adds the provider configured client to the ephemeral resource.
+func (r *externalIDEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the ephemeral resource type name.
+func (r *externalIDEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_external_id"
+}
+
+// Schema defines the schema for the ephemeral resource.
+func (r *externalIDEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads the external ID nOps assumes the integration role of a project with, without storing it in the plan or state. " +
+			"Use it in write-only arguments or provider configurations, requires Terraform 1.10 or later.",
+		Attributes: map[string]schema.Attribute{
+			"project_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "nOps project identifier, usually `nops_project.id`",
+			},
+			"external_id": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Identifier to be used by nOps in order to securely assume a role in the target account",
+			},
+		},
+	}
+}
+
+// Open reads the external ID of the project.
+func (r *externalIDEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data externalIDEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	projects, err := r.client.GetProjects(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote project data",
+			err.Error(),
+		)
+		return
+	}
+	project := lookupProject(projects, data.ProjectID.ValueInt64())
+	if project == nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("project_id"),
+			"Invalid nOps project",
+			fmt.Sprintf("No project with ID %d exists in nOps", data.ProjectID.ValueInt64()),
+		)
+		return
+	}
+
+	data.ExternalID = types.StringValue(project.ExternalID)
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
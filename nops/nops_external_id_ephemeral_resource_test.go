@@ -0,0 +1,52 @@
+package nops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// openExternalID opens the external ID ephemeral resource of the project using the fake nOps APIs.
+func openExternalID(t *testing.T, fake *fakeNops, projectID int64) (externalIDEphemeralResourceModel, ephemeral.OpenResponse) {
+	t.Helper()
+	ctx := context.Background()
+
+	r := &externalIDEphemeralResource{client: fake.client(t)}
+	var schemaResp ephemeral.SchemaResponse
+	r.Schema(ctx, ephemeral.SchemaRequest{}, &schemaResp)
+	s := schemaResp.Schema
+
+	objectType := s.Type().TerraformType(ctx)
+	config := tfsdk.Config{Schema: s, Raw: tftypes.NewValue(objectType, map[string]tftypes.Value{
+		"project_id":  tftypes.NewValue(tftypes.Number, projectID),
+		"external_id": tftypes.NewValue(tftypes.String, nil),
+	})}
+	resp := ephemeral.OpenResponse{Result: tfsdk.EphemeralResultData{Schema: s, Raw: tftypes.NewValue(objectType, nil)}}
+	r.Open(ctx, ephemeral.OpenRequest{Config: config}, &resp)
+
+	var result externalIDEphemeralResourceModel
+	if !resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(resp.Result.Get(ctx, &result)...)
+	}
+
+	return result, resp
+}
+
+func TestExternalIDEphemeralResourceOpen(t *testing.T) {
+	fake := newFakeNops(Project{ID: 1, AccountNumber: "580010171808", ExternalID: "2b4ec7a6-2c5b-4a2e-8f0b-6f8b5ac2b1f1"})
+
+	result, resp := openExternalID(t, fake, 1)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error opening external ID: %v", resp.Diagnostics)
+	}
+	if result.ExternalID.ValueString() != "2b4ec7a6-2c5b-4a2e-8f0b-6f8b5ac2b1f1" || result.ProjectID.ValueInt64() != 1 {
+		t.Errorf("expected the external ID of project 1, got %+v", result)
+	}
+
+	if _, resp := openExternalID(t, fake, 2); !resp.Diagnostics.HasError() {
+		t.Errorf("expected an error opening the external ID of a missing project")
+	}
+}
@@ -0,0 +1,481 @@
+package nops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &gcpProjectResource{}
+	_ resource.ResourceWithConfigure      = &gcpProjectResource{}
+	_ resource.ResourceWithImportState    = &gcpProjectResource{}
+	_ resource.ResourceWithValidateConfig = &gcpProjectResource{}
+)
+
+// Authentication types of the GCP projects.
+const (
+	gcpAuthenticationServiceAccountKey = "service_account_key"
+	gcpAuthenticationWorkloadIdentity  = "workload_identity_federation"
+)
+
+// gcpStatusFailed is the status reported by nOps when it can't read the billing export.
+const gcpStatusFailed = "failed"
+
+var (
+	// gcpBillingAccountPattern matches the identifiers of Cloud Billing accounts.
+	gcpBillingAccountPattern = regexp.MustCompile(`^[0-9A-F]{6}-[0-9A-F]{6}-[0-9A-F]{6}$`)
+	// gcpProjectIDPattern matches the GCP project ID naming rules.
+	gcpProjectIDPattern = regexp.MustCompile(`^[a-z][a-z0-9-]{4,28}[a-z0-9]$`)
+	// bigQueryDatasetPattern matches the characters allowed in BigQuery dataset names.
+	bigQueryDatasetPattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+	// workloadIdentityProviderPattern matches the full resource name of a workload identity pool provider.
+	workloadIdentityProviderPattern = regexp.MustCompile(`^projects/[0-9]+/locations/global/workloadIdentityPools/[a-z0-9-]{4,32}/providers/[a-z0-9-]{4,32}$`)
+	// serviceAccountEmailPattern matches the email of user managed service accounts.
+	serviceAccountEmailPattern = regexp.MustCompile(`^[a-z][a-z0-9-]{4,28}[a-z0-9]@[a-z][a-z0-9-]{4,28}[a-z0-9]\.iam\.gserviceaccount\.com$`)
+)
+
+// gcpProjectResource is the resource implementation.
+type gcpProjectResource struct {
+	client *Client
+}
+
+type gcpProjectModel struct {
+	ID                         types.Int64  `tfsdk:"id"`
+	LastUpdated                types.String `tfsdk:"last_updated"`
+	Name                       types.String `tfsdk:"name"`
+	BillingAccountID           types.String `tfsdk:"billing_account_id"`
+	BigQueryProjectID          types.String `tfsdk:"bigquery_project_id"`
+	BigQueryDatasetID          types.String `tfsdk:"bigquery_dataset_id"`
+	BigQueryTableID            types.String `tfsdk:"bigquery_table_id"`
+	ServiceAccountKey          types.String `tfsdk:"service_account_key"`
+	WorkloadIdentityFederation types.Object `tfsdk:"workload_identity_federation"`
+	AuthenticationType         types.String `tfsdk:"authentication_type"`
+	ServiceAccountEmail        types.String `tfsdk:"service_account_email"`
+	ClientID                   types.Int64  `tfsdk:"client_id"`
+	Status                     types.String `tfsdk:"status"`
+	StatusMessage              types.String `tfsdk:"status_message"`
+	CreatedAt                  types.String `tfsdk:"created_at"`
+	UpdatedAt                  types.String `tfsdk:"updated_at"`
+	IntegratedAt               types.String `tfsdk:"integrated_at"`
+}
+
+type gcpWorkloadIdentityModel struct {
+	WorkloadIdentityProvider types.String `tfsdk:"workload_identity_provider"`
+	ServiceAccountEmail      types.String `tfsdk:"service_account_email"`
+}
+
+// gcpWorkloadIdentityType is the object type of the workload_identity_federation attribute.
+var gcpWorkloadIdentityType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"workload_identity_provider": types.StringType,
+		"service_account_email":      types.StringType,
+	},
+}
+
+// NewGCPProjectResource is a helper function to simplify the provider implementation.
+func NewGCPProjectResource() resource.Resource {
+	return &gcpProjectResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *gcpProjectResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *gcpProjectResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_gcp_project"
+}
+
+// Schema defines the schema for the resource.
+func (r *gcpProjectResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Onboards a GCP Cloud Billing account to the nOps platform. nOps reads its costs from the BigQuery billing export, " +
+			"authenticating with a service account key or through workload identity federation.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "nOps project identifier",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the resource was last updated, as reported by nOps",
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "nOps project name",
+			},
+			"billing_account_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Cloud Billing account to onboard, such as `012345-6789AB-CDEF01`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"bigquery_project_id": schema.StringAttribute{
+				Required:    true,
+				Description: "GCP project hosting the BigQuery dataset of the billing export",
+			},
+			"bigquery_dataset_id": schema.StringAttribute{
+				Required:    true,
+				Description: "BigQuery dataset the standard usage cost export of the billing account is written to",
+			},
+			"bigquery_table_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "BigQuery table of the billing export nOps reads, `gcp_billing_export_v1_<billing_account_id>` with underscores",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"service_account_key": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "JSON key of the service account nOps authenticates as, conflicts with `workload_identity_federation`. Keys created with `google_service_account_key` must be decoded with `base64decode`",
+			},
+			"workload_identity_federation": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "Workload identity federation nOps authenticates through instead of a service account key, conflicts with `service_account_key`",
+				Attributes: map[string]schema.Attribute{
+					"workload_identity_provider": schema.StringAttribute{
+						Required:    true,
+						Description: "Full resource name of the workload identity pool provider trusting nOps, `projects/<project_number>/locations/global/workloadIdentityPools/<pool>/providers/<provider>`",
+					},
+					"service_account_email": schema.StringAttribute{
+						Required:    true,
+						Description: "Email of the service account nOps impersonates through the provider",
+					},
+				},
+			},
+			"authentication_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "How nOps authenticates, `service_account_key` or `workload_identity_federation`",
+			},
+			"service_account_email": schema.StringAttribute{
+				Computed:    true,
+				Description: "Email of the service account nOps authenticates as, it needs the BigQuery Data Viewer and BigQuery Job User roles on the export project",
+			},
+			"client_id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "nOps client identifier, the nOps organization owning the project",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Computed:    true,
+				Description: "Status of the billing export ingestion reported by nOps, such as `pending`, `active` or `failed`",
+			},
+			"status_message": schema.StringAttribute{
+				Computed:    true,
+				Description: "Details about the ingestion status, explaining the failure when `status` is `failed`",
+			},
+			"created_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the project was created in nOps",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"updated_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the project was last updated in nOps, including changes made outside of Terraform",
+			},
+			"integrated_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when nOps first read the billing export, empty while the integration is pending",
+			},
+		},
+	}
+}
+
+// ValidateConfig validates the identifiers follow the GCP formats and a single authentication method is configured.
+func (r *gcpProjectResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config gcpProjectModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	type identifier struct {
+		attribute path.Path
+		value     types.String
+		pattern   *regexp.Regexp
+	}
+	identifiers := []identifier{
+		{path.Root("billing_account_id"), config.BillingAccountID, gcpBillingAccountPattern},
+		{path.Root("bigquery_project_id"), config.BigQueryProjectID, gcpProjectIDPattern},
+		{path.Root("bigquery_dataset_id"), config.BigQueryDatasetID, bigQueryDatasetPattern},
+	}
+
+	var workloadIdentity *gcpWorkloadIdentityModel
+	if !config.WorkloadIdentityFederation.IsNull() && !config.WorkloadIdentityFederation.IsUnknown() {
+		resp.Diagnostics.Append(config.WorkloadIdentityFederation.As(ctx, &workloadIdentity, basetypes.ObjectAsOptions{})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		identifiers = append(identifiers,
+			identifier{path.Root("workload_identity_federation").AtName("workload_identity_provider"), workloadIdentity.WorkloadIdentityProvider, workloadIdentityProviderPattern},
+			identifier{path.Root("workload_identity_federation").AtName("service_account_email"), workloadIdentity.ServiceAccountEmail, serviceAccountEmailPattern},
+		)
+	}
+
+	for _, id := range identifiers {
+		if !id.value.IsNull() && !id.value.IsUnknown() && !id.pattern.MatchString(id.value.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				id.attribute,
+				"Invalid GCP identifier",
+				fmt.Sprintf("%q is not valid, expected it to match %s", id.value.ValueString(), id.pattern),
+			)
+		}
+	}
+
+	if config.ServiceAccountKey.IsUnknown() || config.WorkloadIdentityFederation.IsUnknown() {
+		return
+	}
+	if config.ServiceAccountKey.IsNull() == config.WorkloadIdentityFederation.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("service_account_key"),
+			"Invalid GCP authentication",
+			"Exactly one of service_account_key or workload_identity_federation must be set.",
+		)
+		return
+	}
+	if !config.ServiceAccountKey.IsNull() {
+		if _, err := serviceAccountKeyEmail(config.ServiceAccountKey.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("service_account_key"), "Invalid service account key", err.Error())
+		}
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *gcpProjectResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan gcpProjectModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	update, diags := plan.update(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, err := r.client.CreateGCPProject(ctx, NewGCPProject{BillingAccountID: plan.BillingAccountID.ValueString(), UpdateGCPProject: update})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating GCP project",
+			"Could not create GCP project, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("GCP project %d created for billing account %s with status %s", project.ID, project.BillingAccountID, project.Status))
+	plan.refresh(project)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *gcpProjectResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state gcpProjectModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, err := r.client.GetGCPProject(ctx, state.ID.ValueInt64())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, fmt.Sprintf("GCP project %d wasn't found in nOps, removing it from state", state.ID.ValueInt64()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote GCP project data",
+			err.Error(),
+		)
+		return
+	}
+
+	// nOps never returns the service account key, the configured one is kept.
+	state.Name = types.StringValue(project.Name)
+	state.BillingAccountID = types.StringValue(project.BillingAccountID)
+	state.BigQueryProjectID = types.StringValue(project.BigQueryProjectID)
+	state.BigQueryDatasetID = types.StringValue(project.BigQueryDatasetID)
+	state.WorkloadIdentityFederation = types.ObjectNull(gcpWorkloadIdentityType.AttrTypes)
+	if project.AuthenticationType == gcpAuthenticationWorkloadIdentity {
+		state.WorkloadIdentityFederation, diags = types.ObjectValueFrom(ctx, gcpWorkloadIdentityType.AttrTypes, gcpWorkloadIdentityModel{
+			WorkloadIdentityProvider: types.StringValue(project.WorkloadIdentityProvider),
+			ServiceAccountEmail:      types.StringValue(project.ServiceAccountEmail),
+		})
+		resp.Diagnostics.Append(diags...)
+	}
+	state.refresh(project)
+	if project.Status == gcpStatusFailed {
+		resp.Diagnostics.AddWarning(
+			fmt.Sprintf("nOps can't read the billing export of GCP project %d", state.ID.ValueInt64()),
+			project.StatusMessage,
+		)
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *gcpProjectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Capability to import existing GCP projects into the TF state without recreation, the service account key can't be imported.
+	val, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Error parsing ID for import, please check for a correct project ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), val)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *gcpProjectResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan gcpProjectModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	update, diags := plan.update(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Changing the billing account replaces the project, everything else is updated in place.
+	project, err := r.client.UpdateGCPProject(ctx, plan.ID.ValueInt64(), update)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating GCP project",
+			err.Error(),
+		)
+		return
+	}
+
+	plan.Name = types.StringValue(project.Name)
+	plan.refresh(project)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *gcpProjectResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state gcpProjectModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteGCPProject(ctx, state.ID.ValueInt64())
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error deleting GCP project",
+			err.Error(),
+		)
+		return
+	}
+}
+
+// update returns the nOps payload for the planned project.
+func (m *gcpProjectModel) update(ctx context.Context) (UpdateGCPProject, diag.Diagnostics) {
+	update := UpdateGCPProject{
+		Name:              m.Name.ValueString(),
+		BigQueryProjectID: m.BigQueryProjectID.ValueString(),
+		BigQueryDatasetID: m.BigQueryDatasetID.ValueString(),
+		ServiceAccountKey: m.ServiceAccountKey.ValueString(),
+	}
+	if m.WorkloadIdentityFederation.IsNull() {
+		return update, nil
+	}
+
+	var workloadIdentity gcpWorkloadIdentityModel
+	diags := m.WorkloadIdentityFederation.As(ctx, &workloadIdentity, basetypes.ObjectAsOptions{})
+	update.WorkloadIdentityProvider = workloadIdentity.WorkloadIdentityProvider.ValueString()
+	update.ServiceAccountEmail = workloadIdentity.ServiceAccountEmail.ValueString()
+
+	return update, diags
+}
+
+// refresh sets the attributes computed by nOps.
+func (m *gcpProjectModel) refresh(project *GCPProject) {
+	m.ID = types.Int64Value(int64(project.ID))
+	m.ClientID = types.Int64Value(int64(project.Client))
+	m.BigQueryTableID = types.StringValue(project.BigQueryTableID)
+	m.AuthenticationType = types.StringValue(project.AuthenticationType)
+	m.ServiceAccountEmail = types.StringValue(project.ServiceAccountEmail)
+	m.Status = types.StringValue(project.Status)
+	m.StatusMessage = types.StringValue(project.StatusMessage)
+	m.CreatedAt = serverTimestamp(project.CreatedAt)
+	m.UpdatedAt = serverTimestamp(project.UpdatedAt)
+	m.IntegratedAt = serverTimestamp(project.IntegratedAt)
+	if project.UpdatedAt != "" {
+		m.LastUpdated = serverTimestamp(project.UpdatedAt)
+	} else {
+		m.LastUpdated = types.StringValue(time.Now().Format(time.RFC3339))
+	}
+}
+
+// serviceAccountKeyEmail returns the email of the service account of a JSON key.
+func serviceAccountKeyEmail(key string) (string, error) {
+	var parsed struct {
+		Type        string `json:"type"`
+		ClientEmail string `json:"client_email"`
+	}
+	if err := json.Unmarshal([]byte(key), &parsed); err != nil {
+		// The parsing error isn't returned, it may quote part of the key.
+		return "", fmt.Errorf("the key isn't valid JSON, keys created with google_service_account_key must be decoded with base64decode")
+	}
+	if parsed.Type != "service_account" || parsed.ClientEmail == "" {
+		return "", fmt.Errorf("the key isn't a service account key, expected type service_account with a client_email")
+	}
+
+	return parsed.ClientEmail, nil
+}
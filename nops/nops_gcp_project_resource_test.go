@@ -0,0 +1,195 @@
+package nops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const testServiceAccountKey = `{"type":"service_account","project_id":"nops-billing","client_email":"nops-reader@nops-billing.iam.gserviceaccount.com"}`
+
+func testGCPProjectModel() gcpProjectModel {
+	return gcpProjectModel{
+		ID:                         types.Int64Unknown(),
+		LastUpdated:                types.StringUnknown(),
+		Name:                       types.StringValue("gcp-billing"),
+		BillingAccountID:           types.StringValue("012345-6789AB-CDEF01"),
+		BigQueryProjectID:          types.StringValue("nops-billing"),
+		BigQueryDatasetID:          types.StringValue("billing_export"),
+		BigQueryTableID:            types.StringUnknown(),
+		ServiceAccountKey:          types.StringValue(testServiceAccountKey),
+		WorkloadIdentityFederation: types.ObjectNull(gcpWorkloadIdentityType.AttrTypes),
+		AuthenticationType:         types.StringUnknown(),
+		ServiceAccountEmail:        types.StringUnknown(),
+		ClientID:                   types.Int64Unknown(),
+		Status:                     types.StringUnknown(),
+		StatusMessage:              types.StringUnknown(),
+		CreatedAt:                  types.StringUnknown(),
+		UpdatedAt:                  types.StringUnknown(),
+		IntegratedAt:               types.StringUnknown(),
+	}
+}
+
+// testWorkloadIdentityFederation returns a workload_identity_federation value impersonating the service account.
+func testWorkloadIdentityFederation(t *testing.T, serviceAccountEmail string) types.Object {
+	t.Helper()
+
+	value, diags := types.ObjectValueFrom(context.Background(), gcpWorkloadIdentityType.AttrTypes, gcpWorkloadIdentityModel{
+		WorkloadIdentityProvider: types.StringValue("projects/123456789012/locations/global/workloadIdentityPools/nops-pool/providers/nops-aws"),
+		ServiceAccountEmail:      types.StringValue(serviceAccountEmail),
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected error building workload identity federation: %v", diags)
+	}
+
+	return value
+}
+
+func TestGCPProjectResourceValidateConfig(t *testing.T) {
+	cases := map[string]struct {
+		update         func(*gcpProjectModel)
+		expectedErrors int
+	}{
+		"service account key": {update: func(m *gcpProjectModel) {}},
+		"workload identity federation": {update: func(m *gcpProjectModel) {
+			m.ServiceAccountKey = types.StringNull()
+			m.WorkloadIdentityFederation = testWorkloadIdentityFederation(t, "nops-reader@nops-billing.iam.gserviceaccount.com")
+		}},
+		"both authentications": {update: func(m *gcpProjectModel) {
+			m.WorkloadIdentityFederation = testWorkloadIdentityFederation(t, "nops-reader@nops-billing.iam.gserviceaccount.com")
+		}, expectedErrors: 1},
+		"no authentication": {update: func(m *gcpProjectModel) { m.ServiceAccountKey = types.StringNull() }, expectedErrors: 1},
+		"invalid service account": {update: func(m *gcpProjectModel) {
+			m.ServiceAccountKey = types.StringNull()
+			m.WorkloadIdentityFederation = testWorkloadIdentityFederation(t, "nops-reader@gmail.com")
+		}, expectedErrors: 1},
+		"encoded key": {update: func(m *gcpProjectModel) {
+			m.ServiceAccountKey = types.StringValue("eyJ0eXBlIjoic2VydmljZV9hY2NvdW50In0=")
+		}, expectedErrors: 1},
+		"user credentials key":    {update: func(m *gcpProjectModel) { m.ServiceAccountKey = types.StringValue(`{"type":"authorized_user"}`) }, expectedErrors: 1},
+		"invalid billing account": {update: func(m *gcpProjectModel) { m.BillingAccountID = types.StringValue("012345-6789ab-cdef01") }, expectedErrors: 1},
+		"invalid project":         {update: func(m *gcpProjectModel) { m.BigQueryProjectID = types.StringValue("Nops") }, expectedErrors: 1},
+		"invalid dataset":         {update: func(m *gcpProjectModel) { m.BigQueryDatasetID = types.StringValue("billing-export") }, expectedErrors: 1},
+		"unknown key":             {update: func(m *gcpProjectModel) { m.ServiceAccountKey = types.StringUnknown() }},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, s := newTestResource(t, newFakeNops(), &gcpProjectResource{})
+			config := testGCPProjectModel()
+			c.update(&config)
+
+			var resp fwresource.ValidateConfigResponse
+			r.ValidateConfig(context.Background(), fwresource.ValidateConfigRequest{Config: tfsdk.Config(testResourceState(t, s, &config))}, &resp)
+			if resp.Diagnostics.ErrorsCount() != c.expectedErrors {
+				t.Errorf("expected %d errors, got %v", c.expectedErrors, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestGCPProjectResourceCreate(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops()
+	r, s := newTestResource(t, fake, &gcpProjectResource{})
+
+	plan := testGCPProjectModel()
+	resp := fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error creating GCP project: %v", resp.Diagnostics)
+	}
+
+	var state gcpProjectModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if state.ID.ValueInt64() != 1 || state.BigQueryTableID.ValueString() != "gcp_billing_export_v1_012345_6789AB_CDEF01" {
+		t.Errorf("expected project 1 reading the billing export table, got %s reading %s", state.ID, state.BigQueryTableID)
+	}
+	if state.AuthenticationType.ValueString() != gcpAuthenticationServiceAccountKey || state.ServiceAccountEmail.ValueString() != "nops-reader@nops-billing.iam.gserviceaccount.com" {
+		t.Errorf("expected the service account of the key, got %s with %s", state.AuthenticationType, state.ServiceAccountEmail)
+	}
+	if state.Status.ValueString() != "active" || state.IntegratedAt.ValueString() != "2024-10-21T15:04:05Z" {
+		t.Errorf("expected an active project, got %s integrated at %s", state.Status, state.IntegratedAt)
+	}
+}
+
+func TestGCPProjectResourceUpdateWorkloadIdentity(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops()
+	fake.gcpProjects[3] = GCPProject{ID: 3, Client: 1000, Name: "gcp-billing", BillingAccountID: "012345-6789AB-CDEF01", AuthenticationType: gcpAuthenticationServiceAccountKey}
+	r, s := newTestResource(t, fake, &gcpProjectResource{})
+
+	// Moving from a service account key to workload identity federation doesn't replace the project.
+	plan := testGCPProjectModel()
+	plan.ID = types.Int64Value(3)
+	plan.ServiceAccountKey = types.StringNull()
+	plan.WorkloadIdentityFederation = testWorkloadIdentityFederation(t, "nops-reader@nops-billing.iam.gserviceaccount.com")
+	resp := fwresource.UpdateResponse{State: nullResourceState(s)}
+	r.Update(ctx, fwresource.UpdateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error updating GCP project: %v", resp.Diagnostics)
+	}
+
+	project := fake.gcpProjects[3]
+	if project.AuthenticationType != gcpAuthenticationWorkloadIdentity || project.WorkloadIdentityProvider == "" {
+		t.Errorf("expected the project to authenticate through workload identity federation, got %+v", project)
+	}
+
+	// Reading it back keeps the federation configuration.
+	readResp := fwresource.ReadResponse{State: resp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: resp.State}, &readResp)
+	var state gcpProjectModel
+	readResp.Diagnostics.Append(readResp.State.Get(ctx, &state)...)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error reading GCP project: %v", readResp.Diagnostics)
+	}
+	if !state.WorkloadIdentityFederation.Equal(plan.WorkloadIdentityFederation) || !state.ServiceAccountKey.IsNull() {
+		t.Errorf("expected the workload identity federation to be refreshed, got %s", state.WorkloadIdentityFederation)
+	}
+}
+
+func TestGCPProjectResourceReadFailed(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops()
+	fake.gcpProjects[3] = GCPProject{
+		ID:                  3,
+		Client:              1000,
+		Name:                "gcp-billing",
+		BillingAccountID:    "012345-6789AB-CDEF01",
+		BigQueryProjectID:   "nops-billing",
+		BigQueryDatasetID:   "missing_export",
+		AuthenticationType:  gcpAuthenticationServiceAccountKey,
+		ServiceAccountEmail: "nops-reader@nops-billing.iam.gserviceaccount.com",
+		Status:              gcpStatusFailed,
+		StatusMessage:       "Dataset nops-billing:missing_export was not found",
+	}
+	r, s := newTestResource(t, fake, &gcpProjectResource{})
+
+	// Imported projects only have their identifier in state.
+	state := nullResourceState(s)
+	state.SetAttribute(ctx, path.Root("id"), int64(3))
+	resp := fwresource.ReadResponse{State: state}
+	r.Read(ctx, fwresource.ReadRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error reading GCP project: %v", resp.Diagnostics)
+	}
+
+	var model gcpProjectModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &model)...)
+	if model.BigQueryDatasetID.ValueString() != "missing_export" || !model.WorkloadIdentityFederation.IsNull() || !model.ServiceAccountKey.IsNull() {
+		t.Errorf("expected the imported project to be refreshed, got %+v", model)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("expected a warning about the failed ingestion, got %v", resp.Diagnostics)
+	}
+
+	delete(fake.gcpProjects, 3)
+	r.Read(ctx, fwresource.ReadRequest{State: resp.State}, &resp)
+	if !resp.State.Raw.IsNull() {
+		t.Errorf("expected the project deleted in nOps to be removed from state")
+	}
+}
@@ -0,0 +1,58 @@
+package nops
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &integrationRoleNameFunction{}
+
+// NewIntegrationRoleNameFunction is a helper function to simplify the provider implementation.
+func NewIntegrationRoleNameFunction() function.Function {
+	return &integrationRoleNameFunction{}
+}
+
+// integrationRoleNameFunction is the function implementation.
+type integrationRoleNameFunction struct{}
+
+// Metadata returns the function name.
+func (f *integrationRoleNameFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "integration_role_name"
+}
+
+// Definition defines the function parameters and return type.
+func (f *integrationRoleNameFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Name of the IAM role assumed by nOps",
+		Description: "Returns the name of the IAM role nOps expects to assume in the accounts of a client, `NopsIntegrationRole-<client_id>`.",
+		Parameters: []function.Parameter{
+			function.Int64Parameter{
+				Name:        "client_id",
+				Description: "nOps client identifier, `nops_project.client_id`",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run returns the role name for the client.
+func (f *integrationRoleNameFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var clientID int64
+	resp.Error = req.Arguments.Get(ctx, &clientID)
+	if resp.Error != nil {
+		return
+	}
+	if resp.Error = validateIDArgument(0, "client_id", clientID); resp.Error != nil {
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, integrationRoleName(clientID))
+}
+
+// integrationRoleName returns the name of the IAM role nOps assumes for the client.
+func integrationRoleName(clientID int64) string {
+	return fmt.Sprintf("NopsIntegrationRole-%d", clientID)
+}
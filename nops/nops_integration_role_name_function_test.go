@@ -0,0 +1,43 @@
+package nops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestIntegrationRoleNameFunction(t *testing.T) {
+	cases := map[string]struct {
+		clientID      int64
+		expected      string
+		expectedError bool
+	}{
+		"client":         {clientID: 1000, expected: "NopsIntegrationRole-1000"},
+		"invalid client": {clientID: 0, expectedError: true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			resp := function.RunResponse{Result: function.NewResultData(types.StringUnknown())}
+			NewIntegrationRoleNameFunction().Run(context.Background(), function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.Int64Value(c.clientID)}),
+			}, &resp)
+
+			if c.expectedError {
+				if resp.Error == nil {
+					t.Errorf("expected an error, got %s", resp.Result.Value())
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+			if !resp.Result.Value().Equal(types.StringValue(c.expected)) {
+				t.Errorf("expected %s, got %s", c.expected, resp.Result.Value())
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package nops
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &isPayerFunction{}
+
+// NewIsPayerFunction is a helper function to simplify the provider implementation.
+func NewIsPayerFunction() function.Function {
+	return &isPayerFunction{}
+}
+
+// isPayerFunction is the function implementation.
+type isPayerFunction struct{}
+
+// Metadata returns the function name.
+func (f *isPayerFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "is_payer"
+}
+
+// Definition defines the function parameters and return type.
+func (f *isPayerFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Whether an account is the payer account",
+		Description: "Returns true when the account is the master payer account of its AWS Organization. " +
+			"Only payer accounts host the system bucket receiving the cost reports, member accounts integrate with the bucket name `na`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "account_id",
+				Description: "AWS account ID to check",
+			},
+			function.StringParameter{
+				Name:        "payer_account_id",
+				Description: "AWS account ID of the master payer account of the organization",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+// Run compares the account with the payer account.
+func (f *isPayerFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var accountID, payerAccountID string
+	resp.Error = req.Arguments.Get(ctx, &accountID, &payerAccountID)
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(
+		validateAccountIDArgument(0, "account_id", accountID),
+		validateAccountIDArgument(1, "payer_account_id", payerAccountID),
+	)
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, accountID == payerAccountID)
+}
@@ -0,0 +1,45 @@
+package nops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestIsPayerFunction(t *testing.T) {
+	cases := map[string]struct {
+		accountID      string
+		payerAccountID string
+		expected       bool
+		expectedError  bool
+	}{
+		"payer":         {accountID: "580010171808", payerAccountID: "580010171808", expected: true},
+		"member":        {accountID: "471112641702", payerAccountID: "580010171808", expected: false},
+		"invalid payer": {accountID: "471112641702", payerAccountID: "", expectedError: true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			resp := function.RunResponse{Result: function.NewResultData(types.BoolUnknown())}
+			NewIsPayerFunction().Run(context.Background(), function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(c.accountID), types.StringValue(c.payerAccountID)}),
+			}, &resp)
+
+			if c.expectedError {
+				if resp.Error == nil {
+					t.Errorf("expected an error, got %s", resp.Result.Value())
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+			if !resp.Result.Value().Equal(types.BoolValue(c.expected)) {
+				t.Errorf("expected %t, got %s", c.expected, resp.Result.Value())
+			}
+		})
+	}
+}
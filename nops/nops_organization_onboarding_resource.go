@@ -0,0 +1,549 @@
+package nops
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &organizationOnboardingResource{}
+	_ resource.ResourceWithConfigure = &organizationOnboardingResource{}
+)
+
+const (
+	// organizationProjectPending is reported for projects that still have to be linked with nops_integration.
+	organizationProjectPending = "pending_integration"
+	// organizationProjectIntegrated is reported for projects with an IAM role assigned.
+	organizationProjectIntegrated = "integrated"
+	// organizationProjectFailed is reported for accounts that couldn't be onboarded, they are retried on the next apply.
+	organizationProjectFailed = "failed"
+	// organizationProjectRemovalFailed is reported for removed accounts whose project couldn't be deleted,
+	// the deletion is retried on the next apply.
+	organizationProjectRemovalFailed = "removal_failed"
+)
+
+// organizationOnboardingResource is the resource implementation.
+type organizationOnboardingResource struct {
+	client *Client
+}
+
+type organizationOnboardingModel struct {
+	ID                       types.String `tfsdk:"id"`
+	LastUpdated              types.String `tfsdk:"last_updated"`
+	MasterPayerAccountNumber types.String `tfsdk:"master_payer_account_number"`
+	Accounts                 types.Map    `tfsdk:"accounts"`
+	Projects                 types.Map    `tfsdk:"projects"`
+}
+
+type organizationProjectModel struct {
+	ID         types.Int64  `tfsdk:"id"`
+	Client     types.Int64  `tfsdk:"client"`
+	Name       types.String `tfsdk:"name"`
+	Arn        types.String `tfsdk:"arn"`
+	Bucket     types.String `tfsdk:"bucket"`
+	ExternalID types.String `tfsdk:"external_id"`
+	RoleName   types.String `tfsdk:"role_name"`
+	Adopted    types.Bool   `tfsdk:"adopted"`
+	Status     types.String `tfsdk:"status"`
+}
+
+// organizationProjectType is the object type of each element in the projects attribute.
+var organizationProjectType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"id":          types.Int64Type,
+		"client":      types.Int64Type,
+		"name":        types.StringType,
+		"arn":         types.StringType,
+		"bucket":      types.StringType,
+		"external_id": types.StringType,
+		"role_name":   types.StringType,
+		"adopted":     types.BoolType,
+		"status":      types.StringType,
+	},
+}
+
+// NewOrganizationOnboardingResource is a helper function to simplify the provider implementation.
+func NewOrganizationOnboardingResource() resource.Resource {
+	return &organizationOnboardingResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *organizationOnboardingResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *organizationOnboardingResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_organization_onboarding"
+}
+
+// Schema defines the schema for the resource.
+func (r *organizationOnboardingResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Onboards every member account of an AWS Organization to the nOps platform, creating or adopting one project per account." +
+			" Accounts added to or removed from the map are onboarded or deleted incrementally on each apply.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Onboarding identifier, same as the master payer account id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the resource was last updated",
+			},
+			"master_payer_account_number": schema.StringAttribute{
+				Required:    true,
+				Description: "Master payer AWS account id of the organization",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"accounts": schema.MapAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				Description: "Member AWS account ids to onboard, mapped to the nOps project name to use for each of them",
+			},
+			"projects": schema.MapNestedAttribute{
+				Computed:    true,
+				Description: "Onboarding status of each member account, keyed by AWS account id",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "nOps project identifier",
+						},
+						"client": schema.Int64Attribute{
+							Computed:    true,
+							Description: "nOps client ID",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "nOps project name",
+						},
+						"arn": schema.StringAttribute{
+							Computed:    true,
+							Description: "AWS IAM role ARN to create/update account integration to nOps",
+						},
+						"bucket": schema.StringAttribute{
+							Computed:    true,
+							Description: "AWS S3 bucket name to be used for CUR reports, the initial value is `na`",
+						},
+						"external_id": schema.StringAttribute{
+							Computed:    true,
+							Sensitive:   true,
+							Description: "Identifier to be used by nOps in order to securely assume a role in the target account",
+						},
+						"role_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the IAM role to be used by nOps",
+						},
+						"adopted": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the project was auto discovered by nOps and adopted instead of created",
+						},
+						"status": schema.StringAttribute{
+							Computed:    true,
+							Description: "Onboarding status of the account, one of `pending_integration`, `integrated`, `failed` or `removal_failed` for accounts removed from the map whose project couldn't be deleted yet",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *organizationOnboardingResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan organizationOnboardingModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	accounts := map[string]string{}
+	resp.Diagnostics.Append(plan.Accounts.ElementsAs(ctx, &accounts, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	projects, err := r.client.GetProjects(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error: Error getting remote project data",
+			err.Error(),
+		)
+		return
+	}
+
+	checkIntegratedAccounts(projects, accounts, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	onboarded := r.onboardAccounts(ctx, projects, accounts, plan.MasterPayerAccountNumber.ValueString(), &resp.Diagnostics)
+
+	plan.ID = plan.MasterPayerAccountNumber
+	plan.Projects, diags = types.MapValueFrom(ctx, organizationProjectType, onboarded)
+	resp.Diagnostics.Append(diags...)
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC3339))
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, "Created nOps organization onboarding resource", map[string]any{"ID": plan.ID, "accounts": len(onboarded)})
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *organizationOnboardingResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state organizationOnboardingModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	accounts := map[string]string{}
+	resp.Diagnostics.Append(state.Accounts.ElementsAs(ctx, &accounts, false)...)
+	onboarded := map[string]organizationProjectModel{}
+	resp.Diagnostics.Append(state.Projects.ElementsAs(ctx, &onboarded, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	projects, err := r.client.GetProjects(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote project data",
+			err.Error(),
+		)
+		return
+	}
+
+	for account, current := range onboarded {
+		var upstream *Project
+		for i := range projects {
+			if int64(projects[i].ID) == current.ID.ValueInt64() {
+				upstream = &projects[i]
+			}
+		}
+
+		if upstream == nil {
+			// Failed or externally deleted accounts are dropped so the next plan onboards them again.
+			tflog.Debug(ctx, "Project for account "+account+" not found in nOps, removing it from state")
+			delete(onboarded, account)
+			delete(accounts, account)
+			continue
+		}
+
+		// Projects that couldn't be deleted put their account back, so the next plan retries the removal.
+		accounts[account] = upstream.Name
+		project := organizationProjectValue(*upstream, current.Adopted.ValueBool())
+		if current.Status.ValueString() == organizationProjectRemovalFailed {
+			project.Status = current.Status
+		}
+		onboarded[account] = project
+	}
+
+	state.Accounts, diags = types.MapValueFrom(ctx, types.StringType, accounts)
+	resp.Diagnostics.Append(diags...)
+	state.Projects, diags = types.MapValueFrom(ctx, organizationProjectType, onboarded)
+	resp.Diagnostics.Append(diags...)
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *organizationOnboardingResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan organizationOnboardingModel
+	var currentState organizationOnboardingModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	getStateDiags := req.State.Get(ctx, &currentState)
+	resp.Diagnostics.Append(getStateDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	planned := map[string]string{}
+	resp.Diagnostics.Append(plan.Accounts.ElementsAs(ctx, &planned, false)...)
+	accounts := map[string]string{}
+	resp.Diagnostics.Append(currentState.Accounts.ElementsAs(ctx, &accounts, false)...)
+	onboarded := map[string]organizationProjectModel{}
+	resp.Diagnostics.Append(currentState.Projects.ElementsAs(ctx, &onboarded, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	projects, err := r.client.GetProjects(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote project data",
+			err.Error(),
+		)
+		return
+	}
+
+	// Accounts that failed to be onboarded before are retried along with the new ones.
+	added := map[string]string{}
+	for account, name := range planned {
+		if current, ok := onboarded[account]; !ok || current.ID.ValueInt64() == 0 {
+			added[account] = name
+		}
+	}
+	checkIntegratedAccounts(projects, added, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Removed accounts have their projects deleted, failures keep the projects in state so they're deleted
+	// on the next apply or when the resource is destroyed.
+	var removed []string
+	var removedIDs []int64
+	for _, account := range sortedKeys(onboarded) {
+		if _, ok := planned[account]; ok {
+			continue
+		}
+		if id := onboarded[account].ID.ValueInt64(); id != 0 {
+			removed = append(removed, account)
+			removedIDs = append(removedIDs, id)
+			continue
+		}
+		delete(onboarded, account)
+	}
+	for i, err := range r.client.DeleteProjects(ctx, removedIDs) {
+		account := removed[i]
+		if err != nil && !IsNotFound(err) {
+			resp.Diagnostics.AddWarning(
+				fmt.Sprintf("Error deleting project for AWS account %s", account),
+				"Could not delete project, it will be retried on the next apply. Unexpected error: "+err.Error(),
+			)
+			project := onboarded[account]
+			project.Status = types.StringValue(organizationProjectRemovalFailed)
+			onboarded[account] = project
+			continue
+		}
+		delete(onboarded, account)
+	}
+
+	var renamed []string
+	var updates []BulkUpdateProject
+	for _, account := range sortedKeys(planned) {
+		current, ok := onboarded[account]
+		switch {
+		case !ok || current.ID.ValueInt64() == 0:
+			continue
+		case accounts[account] != planned[account]:
+			renamed = append(renamed, account)
+			updates = append(updates, BulkUpdateProject{
+				ID:            current.ID.ValueInt64(),
+				UpdateProject: UpdateProject{Name: planned[account], AccountNumber: account},
+			})
+		}
+	}
+
+	for i, result := range r.client.UpdateProjects(ctx, updates) {
+		account := renamed[i]
+		if result.Err != nil {
+			resp.Diagnostics.AddError(
+				fmt.Sprintf("Error updating project for AWS account %s", account),
+				result.Err.Error(),
+			)
+			continue
+		}
+		onboarded[account] = organizationProjectValue(*result.Project, onboarded[account].Adopted.ValueBool())
+	}
+
+	for account, project := range r.onboardAccounts(ctx, projects, added, plan.MasterPayerAccountNumber.ValueString(), &resp.Diagnostics) {
+		onboarded[account] = project
+	}
+
+	// The accounts are kept as planned, the outcome of each of them is reported through the projects.
+	plan.Projects, diags = types.MapValueFrom(ctx, organizationProjectType, onboarded)
+	resp.Diagnostics.Append(diags...)
+	plan.ID = currentState.ID
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC3339))
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, "Updated nOps organization onboarding resource", map[string]any{"ID": plan.ID, "accounts": len(onboarded)})
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *organizationOnboardingResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state organizationOnboardingModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	onboarded := map[string]organizationProjectModel{}
+	resp.Diagnostics.Append(state.Projects.ElementsAs(ctx, &onboarded, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var accounts []string
+	var ids []int64
+	for _, account := range sortedKeys(onboarded) {
+		if id := onboarded[account].ID.ValueInt64(); id != 0 {
+			accounts = append(accounts, account)
+			ids = append(ids, id)
+		}
+	}
+
+	for i, err := range r.client.DeleteProjects(ctx, ids) {
+		if err != nil && !IsNotFound(err) {
+			resp.Diagnostics.AddError(
+				fmt.Sprintf("Error deleting project for AWS account %s", accounts[i]),
+				err.Error(),
+			)
+		}
+	}
+}
+
+// onboardAccounts adopts the projects auto discovered by nOps for the accounts, and creates the missing ones in bulk.
+// Failures are reported as warnings and the account is marked as failed, so the remaining accounts are still onboarded.
+func (r *organizationOnboardingResource) onboardAccounts(ctx context.Context, projects []Project, accounts map[string]string, payer string, diags *diag.Diagnostics) map[string]organizationProjectModel {
+	onboarded := map[string]organizationProjectModel{}
+
+	var created []string
+	var newProjects []NewProject
+	for _, account := range sortedKeys(accounts) {
+		if project, pending := lookupAccountProject(projects, account); project != nil && pending {
+			tflog.Debug(ctx, fmt.Sprintf("Project %d pending integration found for account %s, adopting it", project.ID, account))
+			onboarded[account] = organizationProjectValue(*project, true)
+			continue
+		}
+		created = append(created, account)
+		newProjects = append(newProjects, NewProject{
+			Name:                     accounts[account],
+			AccountNumber:            account,
+			MasterPayerAccountNumber: payer,
+		})
+	}
+
+	for i, result := range r.client.CreateProjects(ctx, newProjects) {
+		account := created[i]
+		if result.Err != nil {
+			diags.AddWarning(
+				fmt.Sprintf("Error creating project for AWS account %s", account),
+				"Could not create project, it will be retried on the next apply. Unexpected error: "+result.Err.Error(),
+			)
+			onboarded[account] = organizationProjectModel{
+				ID:         types.Int64Value(0),
+				Client:     types.Int64Value(0),
+				Name:       types.StringValue(accounts[account]),
+				Arn:        types.StringValue(""),
+				Bucket:     types.StringValue(""),
+				ExternalID: types.StringValue(""),
+				RoleName:   types.StringValue(""),
+				Adopted:    types.BoolValue(false),
+				Status:     types.StringValue(organizationProjectFailed),
+			}
+			continue
+		}
+
+		tflog.Debug(ctx, fmt.Sprintf("Upstream project data received for new project %d name: %s", result.Project.ID, result.Project.Name))
+		onboarded[account] = organizationProjectValue(*result.Project, false)
+	}
+
+	return onboarded
+}
+
+// organizationProjectValue maps an upstream project to its onboarding status.
+func organizationProjectValue(project Project, adopted bool) organizationProjectModel {
+	status := organizationProjectIntegrated
+	if project.RoleName == "na" {
+		status = organizationProjectPending
+	}
+
+	return organizationProjectModel{
+		ID:         types.Int64Value(int64(project.ID)),
+		Client:     types.Int64Value(int64(project.Client)),
+		Name:       types.StringValue(project.Name),
+		Arn:        types.StringValue(project.Arn),
+		Bucket:     types.StringValue(project.Bucket),
+		ExternalID: types.StringValue(project.ExternalID),
+		RoleName:   types.StringValue(project.RoleName),
+		Adopted:    types.BoolValue(adopted),
+		Status:     types.StringValue(status),
+	}
+}
+
+// checkIntegratedAccounts refuses to take over projects that already finished their integration, same as nops_project does.
+func checkIntegratedAccounts(projects []Project, accounts map[string]string, diags *diag.Diagnostics) {
+	var conflicts []string
+	for _, account := range sortedKeys(accounts) {
+		if project, pending := lookupAccountProject(projects, account); project != nil && !pending {
+			conflicts = append(conflicts, fmt.Sprintf("%s (project ID %d)", account, project.ID))
+		}
+	}
+	if len(conflicts) > 0 {
+		diags.AddError(
+			"Error: projects already exist for some of the AWS accounts",
+			"Fully integrated projects were found for the following accounts, please remove them from the accounts map or manage them with nops_project: "+
+				strings.Join(conflicts, ", "),
+		)
+	}
+}
+
+// sortedKeys returns the keys of the map in a stable order so API calls are issued deterministically.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,246 @@
+package nops
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestOrganizationOnboardingResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "nops_organization_onboarding" "test" {
+  master_payer_account_number = "580010171808"
+  accounts = {
+    "580010171808" = "automated-testing"
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nops_organization_onboarding.test", "id", "580010171808"),
+					resource.TestCheckResourceAttr("nops_organization_onboarding.test", "projects.%", "1"),
+					resource.TestCheckResourceAttr("nops_organization_onboarding.test", "projects.580010171808.name", "automated-testing"),
+					resource.TestCheckResourceAttrSet("nops_organization_onboarding.test", "projects.580010171808.id"),
+				),
+			},
+			// Incremental add and Read testing
+			{
+				Config: providerConfig + `
+resource "nops_organization_onboarding" "test" {
+  master_payer_account_number = "580010171808"
+  accounts = {
+    "580010171808" = "automated-testing"
+    "471112641702" = "automated-testing-member"
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nops_organization_onboarding.test", "projects.%", "2"),
+					resource.TestCheckResourceAttr("nops_organization_onboarding.test", "projects.471112641702.name", "automated-testing-member"),
+				),
+			},
+		},
+	})
+}
+
+// testOrganizationOnboardingPlan returns the plan of the onboarding of the accounts, mapped to their project names.
+func testOrganizationOnboardingPlan(t *testing.T, id types.String, accounts map[string]string) organizationOnboardingModel {
+	t.Helper()
+
+	value, diags := types.MapValueFrom(context.Background(), types.StringType, accounts)
+	if diags.HasError() {
+		t.Fatalf("unexpected error building accounts: %v", diags)
+	}
+
+	return organizationOnboardingModel{
+		ID:                       id,
+		LastUpdated:              types.StringUnknown(),
+		MasterPayerAccountNumber: types.StringValue("580010171808"),
+		Accounts:                 value,
+		Projects:                 types.MapUnknown(organizationProjectType),
+	}
+}
+
+// organizationOnboardingProjects returns the onboarding status of each account in the state.
+func organizationOnboardingProjects(t *testing.T, state tfsdk.State) (organizationOnboardingModel, map[string]organizationProjectModel) {
+	t.Helper()
+
+	var model organizationOnboardingModel
+	if diags := state.Get(context.Background(), &model); diags.HasError() {
+		t.Fatalf("unexpected error reading state: %v", diags)
+	}
+	projects := map[string]organizationProjectModel{}
+	if diags := model.Projects.ElementsAs(context.Background(), &projects, false); diags.HasError() {
+		t.Fatalf("unexpected error reading projects: %v", diags)
+	}
+
+	return model, projects
+}
+
+func TestOrganizationOnboardingResourceLifecycle(t *testing.T) {
+	ctx := context.Background()
+	// The member account was auto discovered by nOps, its project is adopted.
+	fake := newFakeNops(Project{ID: 1, Name: "discovered", AccountNumber: "471112641702", RoleName: "na"})
+	r, s := newTestResource(t, fake, &organizationOnboardingResource{})
+
+	plan := testOrganizationOnboardingPlan(t, types.StringUnknown(), map[string]string{"471112641702": "member", "580010171808": "payer"})
+	createResp := fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}, &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error onboarding the organization: %v", createResp.Diagnostics)
+	}
+
+	state, projects := organizationOnboardingProjects(t, createResp.State)
+	if state.ID.ValueString() != "580010171808" || len(projects) != 2 {
+		t.Fatalf("expected the two accounts of the organization to be onboarded, got %v", projects)
+	}
+	if member := projects["471112641702"]; member.ID.ValueInt64() != 1 || !member.Adopted.ValueBool() || member.Status.ValueString() != organizationProjectPending {
+		t.Errorf("expected the discovered project to be adopted, got %+v", member)
+	}
+	if payer := projects["580010171808"]; payer.ID.ValueInt64() != 2 || payer.Adopted.ValueBool() {
+		t.Errorf("expected a project to be created for the payer, got %+v", payer)
+	}
+
+	// The member account is removed, a new one is added and the payer project is renamed.
+	plan = testOrganizationOnboardingPlan(t, state.ID, map[string]string{"580010171808": "payer-renamed", "123456789012": "new-member"})
+	updateResp := fwresource.UpdateResponse{State: createResp.State}
+	r.Update(ctx, fwresource.UpdateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan)), State: createResp.State}, &updateResp)
+	if updateResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error updating the onboarding: %v", updateResp.Diagnostics)
+	}
+
+	state, projects = organizationOnboardingProjects(t, updateResp.State)
+	if !state.Accounts.Equal(plan.Accounts) || len(projects) != 2 {
+		t.Errorf("expected the planned accounts to be onboarded, got %s with %v", state.Accounts, projects)
+	}
+	if _, ok := fake.project(1); ok {
+		t.Errorf("expected the project of the removed account to be deleted")
+	}
+	if project, _ := fake.project(2); project.Name != "payer-renamed" || projects["580010171808"].Name.ValueString() != "payer-renamed" {
+		t.Errorf("expected the payer project to be renamed, got %q", project.Name)
+	}
+	if added := projects["123456789012"]; added.ID.ValueInt64() != 3 {
+		t.Errorf("expected a project to be created for the new account, got %+v", added)
+	}
+
+	// Projects already removed in nOps don't fail the destroy.
+	delete(fake.projects, 3)
+	deleteResp := fwresource.DeleteResponse{State: updateResp.State}
+	r.Delete(ctx, fwresource.DeleteRequest{State: updateResp.State}, &deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error deleting the onboarding: %v", deleteResp.Diagnostics)
+	}
+	if len(fake.projects) != 0 {
+		t.Errorf("expected every onboarded project to be deleted, got %v", fake.projects)
+	}
+}
+
+func TestOrganizationOnboardingResourceIntegratedAccount(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(
+		Project{ID: 1, Name: "payer", AccountNumber: "580010171808", RoleName: "na"},
+		Project{ID: 2, Name: "managed-elsewhere", AccountNumber: "471112641702", RoleName: "NopsIntegrationRole"},
+	)
+	r, s := newTestResource(t, fake, &organizationOnboardingResource{})
+
+	plan := testOrganizationOnboardingPlan(t, types.StringUnknown(), map[string]string{"580010171808": "payer", "471112641702": "member"})
+	createResp := fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}, &createResp)
+	if !createResp.Diagnostics.HasError() {
+		t.Fatalf("expected an error onboarding an integrated account")
+	}
+
+	plan = testOrganizationOnboardingPlan(t, types.StringUnknown(), map[string]string{"580010171808": "payer"})
+	createResp = fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}, &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error onboarding the organization: %v", createResp.Diagnostics)
+	}
+
+	// Adding the integrated account later is refused the same way, without creating or adopting its project.
+	state, _ := organizationOnboardingProjects(t, createResp.State)
+	plan = testOrganizationOnboardingPlan(t, state.ID, map[string]string{"580010171808": "payer", "471112641702": "member"})
+	updateResp := fwresource.UpdateResponse{State: createResp.State}
+	r.Update(ctx, fwresource.UpdateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan)), State: createResp.State}, &updateResp)
+	if !updateResp.Diagnostics.HasError() {
+		t.Errorf("expected an error adding an integrated account")
+	}
+	if fake.requested("POST", "/c/admin/projectaws/") || len(fake.projects) != 2 {
+		t.Errorf("expected no project to be created")
+	}
+	if project, _ := fake.project(2); project.Name != "managed-elsewhere" {
+		t.Errorf("expected the integrated project to be left untouched, got %q", project.Name)
+	}
+}
+
+func TestOrganizationOnboardingResourcePartialFailures(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(Project{ID: 1, Name: "member", AccountNumber: "471112641702", RoleName: "na"})
+	fake.failures["POST /c/admin/projectaws/"] = http.StatusInternalServerError
+	r, s := newTestResource(t, fake, &organizationOnboardingResource{})
+
+	// The payer project can't be created, the adopted member account is onboarded anyway.
+	plan := testOrganizationOnboardingPlan(t, types.StringUnknown(), map[string]string{"471112641702": "member", "580010171808": "payer"})
+	createResp := fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}, &createResp)
+	if createResp.Diagnostics.HasError() || createResp.Diagnostics.WarningsCount() != 1 {
+		t.Fatalf("expected a warning about the failed account, got %v", createResp.Diagnostics)
+	}
+	state, projects := organizationOnboardingProjects(t, createResp.State)
+	if !state.Accounts.Equal(plan.Accounts) || projects["580010171808"].Status.ValueString() != organizationProjectFailed {
+		t.Errorf("expected the payer account to be reported failed, got %v", projects)
+	}
+
+	// The next apply retries the failed account, while the member project can't be deleted.
+	delete(fake.failures, "POST /c/admin/projectaws/")
+	fake.failures["DELETE /c/admin/projectaws/1/"] = http.StatusInternalServerError
+	plan = testOrganizationOnboardingPlan(t, state.ID, map[string]string{"580010171808": "payer"})
+	updateResp := fwresource.UpdateResponse{State: createResp.State}
+	r.Update(ctx, fwresource.UpdateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan)), State: createResp.State}, &updateResp)
+	if updateResp.Diagnostics.HasError() || updateResp.Diagnostics.WarningsCount() != 1 {
+		t.Fatalf("expected a warning about the failed removal, got %v", updateResp.Diagnostics)
+	}
+	state, projects = organizationOnboardingProjects(t, updateResp.State)
+	if !state.Accounts.Equal(plan.Accounts) {
+		t.Errorf("expected the accounts to match the plan, got %s", state.Accounts)
+	}
+	if payer := projects["580010171808"]; payer.ID.ValueInt64() != 2 || payer.Status.ValueString() != organizationProjectPending {
+		t.Errorf("expected the payer project to be created, got %+v", payer)
+	}
+	if member := projects["471112641702"]; member.Status.ValueString() != organizationProjectRemovalFailed {
+		t.Errorf("expected the member account to be reported as removal failed, got %+v", member)
+	}
+
+	// Refreshing puts the account back so the removal is planned again.
+	readResp := fwresource.ReadResponse{State: updateResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: updateResp.State}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error reading the onboarding: %v", readResp.Diagnostics)
+	}
+	state, projects = organizationOnboardingProjects(t, readResp.State)
+	if _, ok := state.Accounts.Elements()["471112641702"]; !ok || projects["471112641702"].Status.ValueString() != organizationProjectRemovalFailed {
+		t.Errorf("expected the member account to still be onboarded, got %s with %v", state.Accounts, projects)
+	}
+
+	delete(fake.failures, "DELETE /c/admin/projectaws/1/")
+	updateResp = fwresource.UpdateResponse{State: readResp.State}
+	r.Update(ctx, fwresource.UpdateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan)), State: readResp.State}, &updateResp)
+	if updateResp.Diagnostics.HasError() || updateResp.Diagnostics.WarningsCount() != 0 {
+		t.Fatalf("unexpected diagnostics removing the member account: %v", updateResp.Diagnostics)
+	}
+	if _, projects = organizationOnboardingProjects(t, updateResp.State); len(projects) != 1 {
+		t.Errorf("expected only the payer account to be onboarded, got %v", projects)
+	}
+	if _, ok := fake.project(1); ok {
+		t.Errorf("expected the member project to be deleted")
+	}
+}
@@ -0,0 +1,95 @@
+package nops
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &parseRoleArnFunction{}
+
+// roleArnPattern matches an IAM role ARN, capturing the partition, account ID, path and role name.
+var roleArnPattern = regexp.MustCompile(`^arn:(aws[a-z-]*):iam::(\d{12}):role(/(?:[\x21-\x7E]+/)?)([\w+=,.@-]+)$`)
+
+// roleArnAttributeTypes are the attributes of the object returned by parse_role_arn.
+var roleArnAttributeTypes = map[string]attr.Type{
+	"partition":  types.StringType,
+	"account_id": types.StringType,
+	"path":       types.StringType,
+	"role_name":  types.StringType,
+}
+
+// NewParseRoleArnFunction is a helper function to simplify the provider implementation.
+func NewParseRoleArnFunction() function.Function {
+	return &parseRoleArnFunction{}
+}
+
+// parseRoleArnFunction is the function implementation.
+type parseRoleArnFunction struct{}
+
+// roleArnModel maps the object returned by parse_role_arn.
+type roleArnModel struct {
+	Partition string `tfsdk:"partition"`
+	AccountID string `tfsdk:"account_id"`
+	Path      string `tfsdk:"path"`
+	RoleName  string `tfsdk:"role_name"`
+}
+
+// Metadata returns the function name.
+func (f *parseRoleArnFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_role_arn"
+}
+
+// Definition defines the function parameters and return type.
+func (f *parseRoleArnFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Parse an IAM role ARN",
+		Description: "Splits an IAM role ARN, such as `nops_integration.role_arn`, into an object with the `partition`, `account_id`, `path` and `role_name` attributes.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "arn",
+				Description: "IAM role ARN to parse",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: roleArnAttributeTypes,
+		},
+	}
+}
+
+// Run parses the role ARN.
+func (f *parseRoleArnFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var arn string
+	resp.Error = req.Arguments.Get(ctx, &arn)
+	if resp.Error != nil {
+		return
+	}
+
+	role, err := parseRoleArn(arn)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, role)
+}
+
+// parseRoleArn splits an IAM role ARN into its parts.
+func parseRoleArn(arn string) (roleArnModel, error) {
+	matches := roleArnPattern.FindStringSubmatch(arn)
+	if matches == nil {
+		return roleArnModel{}, fmt.Errorf("%q is not an IAM role ARN, expected arn:<partition>:iam::<account_id>:role/<role_name>", arn)
+	}
+
+	return roleArnModel{
+		Partition: matches[1],
+		AccountID: matches[2],
+		Path:      matches[3],
+		RoleName:  matches[4],
+	}, nil
+}
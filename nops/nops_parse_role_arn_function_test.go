@@ -0,0 +1,56 @@
+package nops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseRoleArnFunction(t *testing.T) {
+	cases := map[string]struct {
+		arn           string
+		expected      map[string]string
+		expectedError bool
+	}{
+		"role": {
+			arn:      "arn:aws:iam::580010171808:role/NopsIntegrationRole-1000",
+			expected: map[string]string{"partition": "aws", "account_id": "580010171808", "path": "/", "role_name": "NopsIntegrationRole-1000"},
+		},
+		"role with path": {
+			arn:      "arn:aws-us-gov:iam::580010171808:role/service-role/nops/NopsIntegrationRole",
+			expected: map[string]string{"partition": "aws-us-gov", "account_id": "580010171808", "path": "/service-role/nops/", "role_name": "NopsIntegrationRole"},
+		},
+		"user":     {arn: "arn:aws:iam::580010171808:user/nops", expectedError: true},
+		"not arn":  {arn: "NopsIntegrationRole", expectedError: true},
+		"no roles": {arn: "arn:aws:iam::580010171808:role/", expectedError: true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			resp := function.RunResponse{Result: function.NewResultData(types.ObjectUnknown(roleArnAttributeTypes))}
+			NewParseRoleArnFunction().Run(context.Background(), function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(c.arn)}),
+			}, &resp)
+
+			if c.expectedError {
+				if resp.Error == nil {
+					t.Errorf("expected an error, got %s", resp.Result.Value())
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+			attributes := map[string]attr.Value{}
+			for attribute, value := range c.expected {
+				attributes[attribute] = types.StringValue(value)
+			}
+			if expected := types.ObjectValueMust(roleArnAttributeTypes, attributes); !resp.Result.Value().Equal(expected) {
+				t.Errorf("expected %s, got %s", expected, resp.Result.Value())
+			}
+		})
+	}
+}
@@ -2,21 +2,30 @@ package nops
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strconv"
-	"time"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &projectIntegrationResource{}
-	_ resource.ResourceWithConfigure = &projectIntegrationResource{}
+	_ resource.Resource                   = &projectIntegrationResource{}
+	_ resource.ResourceWithConfigure      = &projectIntegrationResource{}
+	_ resource.ResourceWithUpgradeState   = &projectIntegrationResource{}
+	_ resource.ResourceWithModifyPlan     = &projectIntegrationResource{}
+	_ resource.ResourceWithValidateConfig = &projectIntegrationResource{}
 )
 
 // projectIntegrationResource is the resource implementation.
@@ -28,9 +37,12 @@ type newProjectIntegrationModel struct {
 	ID           types.Int64  `tfsdk:"id"`
 	LastUpdated  types.String `tfsdk:"last_updated"`
 	ExternalID   types.String `tfsdk:"external_id"`
+	ExternalIDWO types.String `tfsdk:"external_id_wo"`
 	AwsAccountID types.String `tfsdk:"aws_account_id"`
 	RoleArn      types.String `tfsdk:"role_arn"`
 	BucketName   types.String `tfsdk:"bucket_name"`
+	IntegratedAt types.String `tfsdk:"integrated_at"`
+	ProjectID    types.Int64  `tfsdk:"project_id"`
 }
 
 // NewprojectIntegrationResource is a helper function to simplify the provider implementation.
@@ -68,8 +80,10 @@ func (r *projectIntegrationResource) Metadata(_ context.Context, req resource.Me
 // Schema defines the schema for the resource.
 func (r *projectIntegrationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: 1,
 		Description: "Notifies the nOps platform a new account has linked to a project with the required input values." +
-			" This resource is mostly used only for secure connection with nOps APIs.",
+			" This resource is mostly used only for secure connection with nOps APIs." +
+			" nOps assumes the role as soon as it's notified, the notification is retried for up to a minute while the new role propagates in IAM.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
 				Computed:    true,
@@ -77,7 +91,11 @@ func (r *projectIntegrationResource) Schema(_ context.Context, _ resource.Schema
 			},
 			"last_updated": schema.StringAttribute{
 				Computed:    true,
-				Description: "Timestamp when the resource was last updated",
+				Description: "Timestamp when the resource was last updated, as reported by nOps",
+			},
+			"integrated_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the account integration with nOps was completed",
 			},
 			"role_arn": schema.StringAttribute{
 				Required:    true,
@@ -88,17 +106,144 @@ func (r *projectIntegrationResource) Schema(_ context.Context, _ resource.Schema
 				Description: "AWS S3 bucket name to be used for CUR reports",
 			},
 			"external_id": schema.StringAttribute{
-				Required:    true,
-				Description: "Identifier to be used by nOps in order to securely assume a role in the target account",
+				Optional:    true,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Identifier to be used by nOps in order to securely assume a role in the target account, defaults to the external ID of the integrated project",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"external_id_wo": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+				Description: "Write-only variant of `external_id`, which is never stored in the state and conflicts with `external_id`. " +
+					"Changing it alone doesn't update the integration. Requires Terraform 1.11 or later",
 			},
 			"aws_account_id": schema.StringAttribute{
 				Required:    true,
 				Description: "Target AWS account id to integrate with nOps",
 			},
+			"project_id": schema.Int64Attribute{
+				Optional: true,
+				Description: "nOps project identifier to integrate, usually `nops_project.id`, which also orders the integration after the project. " +
+					"Required when more than one project exists for the AWS account, when not set the only project registered for `aws_account_id` is used",
+			},
 		},
 	}
 }
 
+// projectIntegrationModelV0 maps the version 0 schema data, where last_updated was formatted with time.RFC850.
+type projectIntegrationModelV0 struct {
+	ID           types.Int64  `tfsdk:"id"`
+	LastUpdated  types.String `tfsdk:"last_updated"`
+	ExternalID   types.String `tfsdk:"external_id"`
+	AwsAccountID types.String `tfsdk:"aws_account_id"`
+	RoleArn      types.String `tfsdk:"role_arn"`
+	BucketName   types.String `tfsdk:"bucket_name"`
+}
+
+// UpgradeState migrates states written by previous schema versions to the current one.
+func (r *projectIntegrationResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id":             schema.Int64Attribute{Computed: true},
+					"last_updated":   schema.StringAttribute{Computed: true},
+					"role_arn":       schema.StringAttribute{Required: true},
+					"bucket_name":    schema.StringAttribute{Required: true},
+					"external_id":    schema.StringAttribute{Required: true},
+					"aws_account_id": schema.StringAttribute{Required: true},
+				},
+			},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var priorState projectIntegrationModelV0
+				resp.Diagnostics.Append(req.State.Get(ctx, &priorState)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				upgradedState := newProjectIntegrationModel{
+					ID:           priorState.ID,
+					LastUpdated:  upgradeLastUpdated(priorState.LastUpdated),
+					ExternalID:   priorState.ExternalID,
+					AwsAccountID: priorState.AwsAccountID,
+					RoleArn:      priorState.RoleArn,
+					BucketName:   priorState.BucketName,
+				}
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, upgradedState)...)
+			},
+		},
+	}
+}
+
+// ValidateConfig validates a single external ID is configured.
+func (r *projectIntegrationResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config newProjectIntegrationModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.ExternalID.IsNull() && !config.ExternalIDWO.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("external_id_wo"),
+			"Conflicting external IDs",
+			"Only one of external_id and external_id_wo can be set",
+		)
+	}
+}
+
+// ModifyPlan validates the project targeted by project_id exists, and fills the external ID from it when not configured.
+func (r *projectIntegrationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to validate on destroy, or when the project is created in the same apply.
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	// The write-only external ID is never stored, not even through the computed external_id.
+	var configuredExternalIDWO types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("external_id_wo"), &configuredExternalIDWO)...)
+	if !configuredExternalIDWO.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("external_id"), types.StringNull())...)
+		return
+	}
+
+	var plan newProjectIntegrationModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.ProjectID.IsNull() || plan.ProjectID.IsUnknown() || plan.AwsAccountID.IsUnknown() {
+		return
+	}
+
+	projects, err := r.client.GetProjects(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote project data",
+			err.Error(),
+		)
+		return
+	}
+
+	target, err := matchIntegrationProject(projects, plan.AwsAccountID.ValueString(), plan.ProjectID.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("project_id"),
+			"Invalid nOps project",
+			err.Error(),
+		)
+		return
+	}
+
+	var configuredExternalID types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("external_id"), &configuredExternalID)...)
+	if configuredExternalID.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("external_id"), target.ExternalID)...)
+	}
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *projectIntegrationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan newProjectIntegrationModel
@@ -108,20 +253,50 @@ func (r *projectIntegrationResource) Create(ctx context.Context, req resource.Cr
 		return
 	}
 
+	// Find the project targeted by the integration
+	projects, err := r.client.GetProjects(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote project data",
+			err.Error(),
+		)
+		return
+	}
+
+	target, err := matchIntegrationProject(projects, plan.AwsAccountID.ValueString(), plan.ProjectID.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("project_id"),
+			"Error finding the nOps project to integrate",
+			err.Error(),
+		)
+		return
+	}
+	externalID, diags := integrationExternalID(ctx, req.Config, plan.ExternalID, target)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// external_id stays null when external_id_wo is configured.
+	if !plan.ExternalID.IsNull() {
+		plan.ExternalID = types.StringValue(externalID)
+	}
+
 	// Notify nOps with new values
 	var integration Integration
 	integration.RoleArn = plan.RoleArn.ValueString()
 	integration.BucketName = plan.BucketName.ValueString()
 	integration.AccountNumber = plan.AwsAccountID.ValueString()
-	integration.ExternalID = plan.ExternalID.ValueString()
+	integration.ExternalID = externalID
 	integration.RequestType = "Create"
+	integration.ProjectID = int64(target.ID)
 	integration.ResourceProperties = ResourceProperties{
 		ServiceBucket: plan.BucketName.ValueString(),
 		AWSAccountID:  plan.AwsAccountID.ValueString(),
 		RoleArn:       plan.RoleArn.ValueString(),
-		ExternalID:    plan.ExternalID.ValueString(),
+		ExternalID:    externalID,
 	}
-	_, err := r.client.NotifyNops(integration)
+	_, err = r.client.NotifyNops(ctx, integration)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error notifying nOps",
@@ -131,7 +306,7 @@ func (r *projectIntegrationResource) Create(ctx context.Context, req resource.Cr
 	}
 
 	// Get updated project values from nOps
-	projects, err := r.client.GetProjects()
+	projects, err = r.client.GetProjects(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error getting remote project data",
@@ -141,11 +316,12 @@ func (r *projectIntegrationResource) Create(ctx context.Context, req resource.Cr
 	}
 
 	for _, project := range projects {
-		if types.StringValue(project.AccountNumber) == plan.AwsAccountID {
+		if project.ID == target.ID {
 			// Map response body to schema and populate Computed attribute values
 			tflog.Debug(ctx, "Upstream integration project data received for project "+strconv.Itoa(project.ID)+" name: "+project.Name)
 			plan.ID = types.Int64Value(int64(project.ID))
-			plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+			plan.IntegratedAt = serverTimestamp(project.IntegratedAt)
+			plan.LastUpdated = lastUpdated(&project)
 		}
 	}
 
@@ -168,7 +344,7 @@ func (r *projectIntegrationResource) Read(ctx context.Context, req resource.Read
 		return
 	}
 
-	projects, err := r.client.GetProjects()
+	projects, err := r.client.GetProjects(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error getting remote project data",
@@ -177,12 +353,37 @@ func (r *projectIntegrationResource) Read(ctx context.Context, req resource.Read
 		return
 	}
 
-	for _, project := range projects {
-		if types.StringValue(project.AccountNumber) == state.AwsAccountID {
-			// Map response body to schema and populate Computed attribute values
-			tflog.Debug(ctx, "Upstream integration project data received for project "+strconv.Itoa(project.ID)+" name: "+project.Name)
-			state.ID = types.Int64Value(int64(project.ID))
-		}
+	// Prefer the project matched on create, so a second project for the account doesn't change the target.
+	projectID := state.ProjectID.ValueInt64()
+	if projectID == 0 {
+		projectID = state.ID.ValueInt64()
+	}
+	project, err := matchIntegrationProject(projects, state.AwsAccountID.ValueString(), projectID)
+	if errors.Is(err, errProjectNotFound) {
+		// The project was deleted in nOps, the integration needs to be created again.
+		tflog.Warn(ctx, "No project found in nOps for AWS account "+state.AwsAccountID.ValueString()+", removing integration from state")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error finding the integrated nOps project",
+			err.Error(),
+		)
+		return
+	}
+
+	// Map response body to schema and populate Computed attribute values
+	tflog.Debug(ctx, "Upstream integration project data received for project "+strconv.Itoa(project.ID)+" name: "+project.Name)
+	var drift driftDetector
+	state.ID = types.Int64Value(int64(project.ID))
+	state.RoleArn = drift.refresh("role_arn", state.RoleArn, project.Arn)
+	state.BucketName = drift.refresh("bucket_name", state.BucketName, project.Bucket)
+	state.ExternalID = drift.refreshSensitive("external_id", state.ExternalID, project.ExternalID)
+	drift.report(ctx, &resp.Diagnostics, r.client.DriftReport, "nOps integration for AWS account "+project.AccountNumber, project.UpdatedBy, project.UpdatedAt)
+	state.IntegratedAt = serverTimestamp(project.IntegratedAt)
+	if project.UpdatedAt != "" {
+		state.LastUpdated = lastUpdated(project)
 	}
 
 	// Set refreshed state
@@ -196,26 +397,67 @@ func (r *projectIntegrationResource) Read(ctx context.Context, req resource.Read
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *projectIntegrationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var plan newProjectIntegrationModel
+	var currentState newProjectIntegrationModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	getStateDiags := req.State.Get(ctx, &currentState)
+	resp.Diagnostics.Append(getStateDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Find the project targeted by the integration
+	projects, err := r.client.GetProjects(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote project data",
+			err.Error(),
+		)
+		return
+	}
+
+	// Keep targeting the project integrated on create unless project_id is set.
+	projectID := plan.ProjectID.ValueInt64()
+	if projectID == 0 && currentState.AwsAccountID.Equal(plan.AwsAccountID) {
+		projectID = currentState.ID.ValueInt64()
+	}
+	target, err := matchIntegrationProject(projects, plan.AwsAccountID.ValueString(), projectID)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("project_id"),
+			"Error finding the nOps project to integrate",
+			err.Error(),
+		)
+		return
+	}
+	externalID, diags := integrationExternalID(ctx, req.Config, plan.ExternalID, target)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// external_id stays null when external_id_wo is configured.
+	if !plan.ExternalID.IsNull() {
+		plan.ExternalID = types.StringValue(externalID)
+	}
 
 	// Notify nOps with updated values
 	var integration Integration
 	integration.RoleArn = plan.RoleArn.ValueString()
 	integration.BucketName = plan.BucketName.ValueString()
 	integration.AccountNumber = plan.AwsAccountID.ValueString()
-	integration.ExternalID = plan.ExternalID.ValueString()
+	integration.ExternalID = externalID
 	integration.RequestType = "Update"
+	integration.ProjectID = int64(target.ID)
 	integration.ResourceProperties = ResourceProperties{
 		ServiceBucket: plan.BucketName.ValueString(),
 		AWSAccountID:  plan.AwsAccountID.ValueString(),
 		RoleArn:       plan.RoleArn.ValueString(),
-		ExternalID:    plan.ExternalID.ValueString(),
+		ExternalID:    externalID,
 	}
-	_, err := r.client.NotifyNops(integration)
+	_, err = r.client.NotifyNops(ctx, integration)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating nOps project",
@@ -225,7 +467,7 @@ func (r *projectIntegrationResource) Update(ctx context.Context, req resource.Up
 	}
 
 	// Get updated project values from nOps
-	projects, err := r.client.GetProjects()
+	projects, err = r.client.GetProjects(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error getting remote project data",
@@ -235,11 +477,12 @@ func (r *projectIntegrationResource) Update(ctx context.Context, req resource.Up
 	}
 
 	for _, project := range projects {
-		if types.StringValue(project.AccountNumber) == plan.AwsAccountID {
+		if project.ID == target.ID {
 			// Map response body to schema and populate Computed attribute values
 			tflog.Debug(ctx, "Upstream integration project data received for project "+strconv.Itoa(project.ID)+" name: "+project.Name)
 			plan.ID = types.Int64Value(int64(project.ID))
-			plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+			plan.IntegratedAt = serverTimestamp(project.IntegratedAt)
+			plan.LastUpdated = lastUpdated(&project)
 		}
 	}
 
@@ -249,7 +492,7 @@ func (r *projectIntegrationResource) Update(ctx context.Context, req resource.Up
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	tflog.Info(ctx, "Updated nOps integration resource", map[string]any{"ID": plan.ID, "ExternalID": plan.ExternalID, "LastUpdated": plan.LastUpdated})
+	tflog.Info(ctx, "Updated nOps integration resource", map[string]any{"ID": plan.ID, "LastUpdated": plan.LastUpdated})
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
@@ -268,3 +511,56 @@ func (r *projectIntegrationResource) ImportState(ctx context.Context, req resour
 	// Capability to import existing project already integrated in the nOps platform into the TF state without recreation.
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("aws_account_id"), req.ID)...)
 }
+
+// errProjectNotFound is returned when no project matches the integration.
+var errProjectNotFound = errors.New("project not found")
+
+// integrationExternalID returns the external ID nOps assumes the role with: the configured external_id_wo, otherwise
+// the planned external_id, defaulting to the external ID of the integrated project.
+func integrationExternalID(ctx context.Context, config tfsdk.Config, planned types.String, target *Project) (string, diag.Diagnostics) {
+	var writeOnly types.String
+	diags := config.GetAttribute(ctx, path.Root("external_id_wo"), &writeOnly)
+	if !writeOnly.IsNull() {
+		return writeOnly.ValueString(), diags
+	}
+	if planned.IsNull() || planned.IsUnknown() {
+		return target.ExternalID, diags
+	}
+
+	return planned.ValueString(), diags
+}
+
+// matchIntegrationProject returns the project targeted by an integration: the one with projectID when set,
+// otherwise the only project registered for the AWS account. More than one project for the account is
+// ambiguous and reported with the candidates, so the integration never targets an arbitrary one.
+func matchIntegrationProject(projects []Project, accountNumber string, projectID int64) (*Project, error) {
+	var candidates []*Project
+	for i := range projects {
+		if projects[i].AccountNumber != accountNumber {
+			continue
+		}
+		if projectID != 0 && int64(projects[i].ID) == projectID {
+			return &projects[i], nil
+		}
+		candidates = append(candidates, &projects[i])
+	}
+
+	if projectID != 0 {
+		return nil, fmt.Errorf("%w: no project with ID %d exists for AWS account %s", errProjectNotFound, projectID, accountNumber)
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("%w: no project exists for AWS account %s, please create it first with nops_project", errProjectNotFound, accountNumber)
+	case 1:
+		return candidates[0], nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].ID < candidates[j].ID })
+	names := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		names[i] = fmt.Sprintf("%d (%s)", candidate.ID, candidate.Name)
+	}
+
+	return nil, fmt.Errorf("%d projects exist for AWS account %s, set project_id to one of: %s", len(candidates), accountNumber, strings.Join(names, ", "))
+}
@@ -0,0 +1,316 @@
+package nops
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestProjectIntegrationResourceUpgradeStateV0(t *testing.T) {
+	ctx := context.Background()
+	r, s := newTestResource(t, newFakeNops(), &projectIntegrationResource{})
+
+	upgrader := r.UpgradeState(ctx)[0]
+	priorState := testResourceState(t, *upgrader.PriorSchema, &projectIntegrationModelV0{
+		ID:           types.Int64Value(1),
+		LastUpdated:  types.StringValue("Monday, 21-Oct-24 15:04:05 UTC"),
+		ExternalID:   types.StringValue("external-id"),
+		AwsAccountID: types.StringValue("580010171808"),
+		RoleArn:      types.StringValue("arn:aws:iam::580010171808:role/NopsIntegrationRole"),
+		BucketName:   types.StringValue("na"),
+	})
+
+	resp := fwresource.UpgradeStateResponse{State: nullResourceState(s)}
+	upgrader.StateUpgrader(ctx, fwresource.UpgradeStateRequest{State: &priorState}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error upgrading state: %v", resp.Diagnostics)
+	}
+
+	var state newProjectIntegrationModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error reading upgraded state: %v", resp.Diagnostics)
+	}
+	if state.LastUpdated.ValueString() != "2024-10-21T15:04:05Z" {
+		t.Errorf("expected last_updated in RFC3339, got %s", state.LastUpdated)
+	}
+	if state.RoleArn.ValueString() != "arn:aws:iam::580010171808:role/NopsIntegrationRole" || state.ID.ValueInt64() != 1 {
+		t.Errorf("expected prior values to be kept, got %+v", state)
+	}
+}
+
+func TestUpgradeLastUpdated(t *testing.T) {
+	cases := map[string]struct {
+		value    types.String
+		expected types.String
+	}{
+		"rfc850":   {value: types.StringValue("Tuesday, 05-Nov-24 08:30:00 UTC"), expected: types.StringValue("2024-11-05T08:30:00Z")},
+		"rfc3339":  {value: types.StringValue("2024-11-05T08:30:00Z"), expected: types.StringValue("2024-11-05T08:30:00Z")},
+		"null":     {value: types.StringNull(), expected: types.StringNull()},
+		"unparsed": {value: types.StringValue("yesterday"), expected: types.StringValue("yesterday")},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if actual := upgradeLastUpdated(tc.value); !actual.Equal(tc.expected) {
+				t.Errorf("expected %s, got %s", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func testIntegrationModel() newProjectIntegrationModel {
+	return newProjectIntegrationModel{
+		ID:           types.Int64Value(1),
+		LastUpdated:  types.StringValue("2024-10-21T15:04:05Z"),
+		ExternalID:   types.StringValue("external-id"),
+		AwsAccountID: types.StringValue("580010171808"),
+		RoleArn:      types.StringValue("arn:aws:iam::580010171808:role/NopsIntegrationRole"),
+		BucketName:   types.StringValue("nops-system-bucket"),
+	}
+}
+
+func TestProjectIntegrationResourceReadDrift(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(Project{
+		ID:            1,
+		AccountNumber: "580010171808",
+		Arn:           "arn:aws:iam::580010171808:role/RepointedRole",
+		Bucket:        "another-bucket",
+		ExternalID:    "external-id",
+		UpdatedAt:     fakeNopsTimestamp,
+	})
+	r, s := newTestResource(t, fake, &projectIntegrationResource{})
+	r.client.DriftReport = true
+
+	model := testIntegrationModel()
+	resp := fwresource.ReadResponse{State: testResourceState(t, s, &model)}
+	r.Read(ctx, fwresource.ReadRequest{State: resp.State}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error reading integration: %v", resp.Diagnostics)
+	}
+
+	var state newProjectIntegrationModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if state.RoleArn.ValueString() != "arn:aws:iam::580010171808:role/RepointedRole" || state.BucketName.ValueString() != "another-bucket" {
+		t.Errorf("expected role and bucket to be refreshed, got %s and %s", state.RoleArn, state.BucketName)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("expected a drift warning, got %v", resp.Diagnostics)
+	}
+}
+
+func TestProjectIntegrationResourceReadDriftSensitive(t *testing.T) {
+	fake := newFakeNops(Project{
+		ID:            1,
+		AccountNumber: "580010171808",
+		Arn:           "arn:aws:iam::580010171808:role/NopsIntegrationRole",
+		Bucket:        "nops-system-bucket",
+		ExternalID:    "rotated-external-id",
+	})
+	r, s := newTestResource(t, fake, &projectIntegrationResource{})
+	r.client.DriftReport = true
+
+	model := testIntegrationModel()
+	resp := fwresource.ReadResponse{State: testResourceState(t, s, &model)}
+	r.Read(context.Background(), fwresource.ReadRequest{State: resp.State}, &resp)
+	warnings := resp.Diagnostics.Warnings()
+	if len(warnings) != 1 {
+		t.Fatalf("expected a drift warning, got %v", resp.Diagnostics)
+	}
+	if detail := warnings[0].Detail(); !strings.Contains(detail, "external_id") || strings.Contains(detail, "external-id") {
+		t.Errorf("expected the external_id drift to be reported without its values, got %s", detail)
+	}
+}
+
+func TestProjectIntegrationResourceReadRemoved(t *testing.T) {
+	fake := newFakeNops(Project{ID: 2, AccountNumber: "471112641702"})
+	r, s := newTestResource(t, fake, &projectIntegrationResource{})
+
+	model := testIntegrationModel()
+	resp := fwresource.ReadResponse{State: testResourceState(t, s, &model)}
+	r.Read(context.Background(), fwresource.ReadRequest{State: resp.State}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error reading integration: %v", resp.Diagnostics)
+	}
+	if !resp.State.Raw.IsNull() {
+		t.Errorf("expected integration to be removed from state")
+	}
+}
+
+func TestMatchIntegrationProject(t *testing.T) {
+	projects := []Project{
+		{ID: 3, Name: "payer-b", AccountNumber: "580010171808"},
+		{ID: 1, Name: "payer-a", AccountNumber: "580010171808"},
+		{ID: 2, Name: "member", AccountNumber: "471112641702"},
+	}
+
+	cases := map[string]struct {
+		accountNumber string
+		projectID     int64
+		expectedID    int
+		expectedError string
+	}{
+		"single project":        {accountNumber: "471112641702", expectedID: 2},
+		"explicit project":      {accountNumber: "580010171808", projectID: 3, expectedID: 3},
+		"ambiguous":             {accountNumber: "580010171808", expectedError: "set project_id to one of: 1 (payer-a), 3 (payer-b)"},
+		"no project":            {accountNumber: "123456789012", expectedError: "no project exists for AWS account 123456789012"},
+		"project of other acct": {accountNumber: "471112641702", projectID: 3, expectedError: "no project with ID 3 exists for AWS account 471112641702"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			project, err := matchIntegrationProject(projects, tc.accountNumber, tc.projectID)
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("expected error containing %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if project.ID != tc.expectedID {
+				t.Errorf("expected project %d, got %d", tc.expectedID, project.ID)
+			}
+		})
+	}
+}
+
+func TestProjectIntegrationResourceCreateProjectID(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(
+		Project{ID: 1, Name: "payer-a", AccountNumber: "580010171808", RoleName: "na"},
+		Project{ID: 2, Name: "payer-b", AccountNumber: "580010171808", RoleName: "na"},
+	)
+	r, s := newTestResource(t, fake, &projectIntegrationResource{})
+
+	model := testIntegrationModel()
+	model.ID = types.Int64Unknown()
+	model.LastUpdated = types.StringUnknown()
+	model.IntegratedAt = types.StringUnknown()
+
+	// Ambiguous without project_id
+	resp := fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(ctx, fwresource.CreateRequest{
+		Config: tfsdk.Config(testResourceState(t, s, &model)),
+		Plan:   tfsdk.Plan(testResourceState(t, s, &model)),
+	}, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("expected an error for ambiguous projects")
+	}
+	if fake.requested("POST", "/c/aws/integration/") {
+		t.Errorf("expected nOps not to be notified for ambiguous projects")
+	}
+
+	model.ProjectID = types.Int64Value(2)
+	resp = fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(ctx, fwresource.CreateRequest{
+		Config: tfsdk.Config(testResourceState(t, s, &model)),
+		Plan:   tfsdk.Plan(testResourceState(t, s, &model)),
+	}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error creating integration: %v", resp.Diagnostics)
+	}
+
+	var state newProjectIntegrationModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if state.ID.ValueInt64() != 2 {
+		t.Errorf("expected project 2 to be integrated, got %s", state.ID)
+	}
+	if project, _ := fake.project(1); project.RoleName != "na" {
+		t.Errorf("expected project 1 to be left untouched, got role %q", project.RoleName)
+	}
+}
+
+func TestProjectIntegrationResourceCreateExternalIDWO(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(Project{ID: 1, AccountNumber: "580010171808", ExternalID: "project-external-id", RoleName: "na"})
+	r, s := newTestResource(t, fake, &projectIntegrationResource{})
+
+	config := testIntegrationModel()
+	config.ID = types.Int64Null()
+	config.LastUpdated = types.StringNull()
+	config.ExternalID = types.StringNull()
+	config.ExternalIDWO = types.StringValue("write-only-external-id")
+	plan := config
+	plan.ID = types.Int64Unknown()
+	plan.LastUpdated = types.StringUnknown()
+	plan.IntegratedAt = types.StringUnknown()
+	plan.ExternalIDWO = types.StringNull()
+
+	resp := fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(ctx, fwresource.CreateRequest{
+		Config: tfsdk.Config(testResourceState(t, s, &config)),
+		Plan:   tfsdk.Plan(testResourceState(t, s, &plan)),
+	}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error creating integration: %v", resp.Diagnostics)
+	}
+
+	if len(fake.integrations) != 1 || fake.integrations[0].ExternalID != "write-only-external-id" {
+		t.Errorf("expected nOps to be notified with the write-only external ID, got %+v", fake.integrations)
+	}
+	var state newProjectIntegrationModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if !state.ExternalID.IsNull() || !state.ExternalIDWO.IsNull() {
+		t.Errorf("expected the write-only external ID not to be stored, got %s and %s", state.ExternalID, state.ExternalIDWO)
+	}
+
+	// Both external IDs conflict
+	config.ExternalID = types.StringValue("external-id")
+	var validateResp fwresource.ValidateConfigResponse
+	r.ValidateConfig(ctx, fwresource.ValidateConfigRequest{Config: tfsdk.Config(testResourceState(t, s, &config))}, &validateResp)
+	if !validateResp.Diagnostics.HasError() {
+		t.Errorf("expected an error when both external_id and external_id_wo are set")
+	}
+}
+
+func TestProjectIntegrationResourceModifyPlan(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(Project{ID: 1, AccountNumber: "580010171808", ExternalID: "project-external-id", RoleName: "na"})
+	r, s := newTestResource(t, fake, &projectIntegrationResource{})
+
+	config := newProjectIntegrationModel{
+		AwsAccountID: types.StringValue("580010171808"),
+		RoleArn:      types.StringValue("arn:aws:iam::580010171808:role/NopsIntegrationRole"),
+		BucketName:   types.StringValue("na"),
+		ProjectID:    types.Int64Value(1),
+	}
+	plan := config
+	plan.ID = types.Int64Unknown()
+	plan.LastUpdated = types.StringUnknown()
+	plan.IntegratedAt = types.StringUnknown()
+	plan.ExternalID = types.StringUnknown()
+
+	resp := fwresource.ModifyPlanResponse{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}
+	r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{
+		Config: tfsdk.Config(testResourceState(t, s, &config)),
+		Plan:   resp.Plan,
+	}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error modifying plan: %v", resp.Diagnostics)
+	}
+
+	var externalID types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("external_id"), &externalID)...)
+	if externalID.ValueString() != "project-external-id" {
+		t.Errorf("expected external_id to default from the project, got %s", externalID)
+	}
+
+	config.ProjectID = types.Int64Value(99)
+	plan.ProjectID = types.Int64Value(99)
+	resp = fwresource.ModifyPlanResponse{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}
+	r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{
+		Config: tfsdk.Config(testResourceState(t, s, &config)),
+		Plan:   resp.Plan,
+	}, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("expected an error for a project that doesn't exist")
+	}
+}
@@ -9,15 +9,30 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &projectResource{}
-	_ resource.ResourceWithConfigure   = &projectResource{}
-	_ resource.ResourceWithImportState = &projectResource{}
+	_ resource.Resource                 = &projectResource{}
+	_ resource.ResourceWithConfigure    = &projectResource{}
+	_ resource.ResourceWithImportState  = &projectResource{}
+	_ resource.ResourceWithModifyPlan   = &projectResource{}
+	_ resource.ResourceWithUpgradeState = &projectResource{}
+)
+
+// Warning returned when upgrading states written before client_id and project_id existed, configurations
+// referencing client as the project identifier, such as the previous examples, must be updated.
+const (
+	clientDeprecationSummary = "nops_project client attribute is deprecated"
+	clientDeprecationDetail  = "The client attribute holds the nOps client identifier, not the project identifier. " +
+		"Use client_id for the client identifier and project_id, or id, for the project identifier. client will be removed in a future version."
 )
 
 // projectResource is the resource implementation.
@@ -36,6 +51,19 @@ type ProjectModel struct {
 	Client                   types.Int64  `tfsdk:"client"`
 	ExternalID               types.String `tfsdk:"external_id"`
 	RoleName                 types.String `tfsdk:"role_name"`
+	AdoptExisting            types.Bool   `tfsdk:"adopt_existing"`
+	Adopted                  types.Bool   `tfsdk:"adopted"`
+	RetainOnDestroy          types.Bool   `tfsdk:"retain_on_destroy"`
+	DeletionProtection       types.Bool   `tfsdk:"deletion_protection"`
+	CreatedAt                types.String `tfsdk:"created_at"`
+	UpdatedAt                types.String `tfsdk:"updated_at"`
+	IntegratedAt             types.String `tfsdk:"integrated_at"`
+	ProjectID                types.Int64  `tfsdk:"project_id"`
+	ClientID                 types.Int64  `tfsdk:"client_id"`
+	IntegrationRoleName      types.String `tfsdk:"integration_role_name"`
+	SystemBucketName         types.String `tfsdk:"system_bucket_name"`
+	IsPayer                  types.Bool   `tfsdk:"is_payer"`
+	PayerProjectID           types.Int64  `tfsdk:"payer_project_id"`
 }
 
 // NewProjectResource is a helper function to simplify the provider implementation.
@@ -73,6 +101,7 @@ func (r *projectResource) Metadata(_ context.Context, req resource.MetadataReque
 // Schema defines the schema for the resource.
 func (r *projectResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version:     2,
 		Description: "Resource intended to be used for the initial onboarding of an account to the nOps platform, used for communication with nOps APIs.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
@@ -81,7 +110,22 @@ func (r *projectResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 			},
 			"last_updated": schema.StringAttribute{
 				Computed:    true,
-				Description: "Timestamp when the resource was last updated",
+				Description: "Timestamp when the resource was last updated, as reported by nOps",
+			},
+			"created_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the project was created in nOps",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"updated_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the project was last updated in nOps, including changes made outside of Terraform",
+			},
+			"integrated_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the account integration with nOps was completed, empty while the integration is pending",
 			},
 			"name": schema.StringAttribute{
 				Required:    true,
@@ -97,11 +141,52 @@ func (r *projectResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 			},
 			"master_payer_account_number": schema.StringAttribute{
 				Required:    true,
-				Description: "Master payer AWS account id used to conditionally create resources",
+				Description: "Master payer AWS account id of the organization of the account, the same as `account_number` for payer accounts. The payer account must be onboarded to nOps before its linked accounts",
+			},
+			"is_payer": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the account is the master payer account of its organization, `account_number` equals `master_payer_account_number`",
+			},
+			"payer_project_id": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "nOps project identifier of the master payer account, the project itself for payer accounts. Set it to `nops_project.<payer>.id` to onboard a linked account in the same apply as its payer, otherwise it is looked up from `master_payer_account_number`",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
 			},
 			"client": schema.Int64Attribute{
+				Computed:           true,
+				Description:        "nOps client ID",
+				DeprecationMessage: clientDeprecationDetail,
+			},
+			"project_id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "nOps project identifier, the same as `id`",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"client_id": schema.Int64Attribute{
 				Computed:    true,
-				Description: "nOps client ID",
+				Description: "nOps client identifier, the nOps organization owning the project",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"integration_role_name": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the IAM role nOps expects to assume in the account, `NopsIntegrationRole-<client_id>`. Computed by the provider from the nOps naming convention, the API doesn't report it",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"system_bucket_name": schema.StringAttribute{
+				Computed:    true,
+				Description: "Canonical name of the system bucket of the project, `nops-<client_id>-<project_id>-<account_id>`. Computed by the provider from the nOps naming convention, the API doesn't report it",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"arn": schema.StringAttribute{
 				Computed:    true,
@@ -113,10 +198,262 @@ func (r *projectResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 			},
 			"external_id": schema.StringAttribute{
 				Computed:    true,
+				Sensitive:   true,
 				Description: "Identifier to be used by nOps in order to securely assume a role in the target account",
 			},
+			"adopt_existing": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Take ownership of a project already integrated for the AWS account instead of failing on create, its name is updated to match the configuration",
+			},
+			"adopted": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the project already existed in nOps and was adopted instead of created",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"retain_on_destroy": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Only remove the project from the Terraform state on destroy, leaving it in place in nOps",
+			},
+			"deletion_protection": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Refuse to destroy the project, which would delete its cost history in nOps. Must be disabled and applied before destroying",
+			},
+		},
+	}
+}
+
+// projectModelV0 maps the version 0 schema data, where last_updated was formatted with time.RFC850.
+type projectModelV0 struct {
+	ID                       types.Int64  `tfsdk:"id"`
+	LastUpdated              types.String `tfsdk:"last_updated"`
+	Name                     types.String `tfsdk:"name"`
+	AccountNumber            types.String `tfsdk:"account_number"`
+	MasterPayerAccountNumber types.String `tfsdk:"master_payer_account_number"`
+	Arn                      types.String `tfsdk:"arn"`
+	Bucket                   types.String `tfsdk:"bucket"`
+	Client                   types.Int64  `tfsdk:"client"`
+	ExternalID               types.String `tfsdk:"external_id"`
+	RoleName                 types.String `tfsdk:"role_name"`
+	AdoptExisting            types.Bool   `tfsdk:"adopt_existing"`
+	Adopted                  types.Bool   `tfsdk:"adopted"`
+	RetainOnDestroy          types.Bool   `tfsdk:"retain_on_destroy"`
+	DeletionProtection       types.Bool   `tfsdk:"deletion_protection"`
+}
+
+// projectModelV1 maps the version 1 schema data, before the identifiers and derived names were exposed.
+type projectModelV1 struct {
+	ID                       types.Int64  `tfsdk:"id"`
+	LastUpdated              types.String `tfsdk:"last_updated"`
+	Name                     types.String `tfsdk:"name"`
+	AccountNumber            types.String `tfsdk:"account_number"`
+	MasterPayerAccountNumber types.String `tfsdk:"master_payer_account_number"`
+	Arn                      types.String `tfsdk:"arn"`
+	Bucket                   types.String `tfsdk:"bucket"`
+	Client                   types.Int64  `tfsdk:"client"`
+	ExternalID               types.String `tfsdk:"external_id"`
+	RoleName                 types.String `tfsdk:"role_name"`
+	AdoptExisting            types.Bool   `tfsdk:"adopt_existing"`
+	Adopted                  types.Bool   `tfsdk:"adopted"`
+	RetainOnDestroy          types.Bool   `tfsdk:"retain_on_destroy"`
+	DeletionProtection       types.Bool   `tfsdk:"deletion_protection"`
+	CreatedAt                types.String `tfsdk:"created_at"`
+	UpdatedAt                types.String `tfsdk:"updated_at"`
+	IntegratedAt             types.String `tfsdk:"integrated_at"`
+}
+
+// UpgradeState migrates states written by previous schema versions to the current one.
+func (r *projectResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id":                          schema.Int64Attribute{Computed: true},
+					"last_updated":                schema.StringAttribute{Computed: true},
+					"name":                        schema.StringAttribute{Required: true},
+					"account_number":              schema.StringAttribute{Required: true},
+					"role_name":                   schema.StringAttribute{Computed: true},
+					"master_payer_account_number": schema.StringAttribute{Required: true},
+					"client":                      schema.Int64Attribute{Computed: true},
+					"arn":                         schema.StringAttribute{Computed: true},
+					"bucket":                      schema.StringAttribute{Computed: true},
+					"external_id":                 schema.StringAttribute{Computed: true},
+					"adopt_existing":              schema.BoolAttribute{Optional: true, Computed: true},
+					"adopted":                     schema.BoolAttribute{Computed: true},
+					"retain_on_destroy":           schema.BoolAttribute{Optional: true, Computed: true},
+					"deletion_protection":         schema.BoolAttribute{Optional: true, Computed: true},
+				},
+			},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var priorState projectModelV0
+				resp.Diagnostics.Append(req.State.Get(ctx, &priorState)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				// States written before adopt_existing, retain_on_destroy and deletion_protection existed don't have them set.
+				upgradedState := ProjectModel{
+					ID:                       priorState.ID,
+					LastUpdated:              upgradeLastUpdated(priorState.LastUpdated),
+					Name:                     priorState.Name,
+					AccountNumber:            priorState.AccountNumber,
+					MasterPayerAccountNumber: priorState.MasterPayerAccountNumber,
+					Arn:                      priorState.Arn,
+					Bucket:                   priorState.Bucket,
+					Client:                   priorState.Client,
+					ExternalID:               priorState.ExternalID,
+					RoleName:                 priorState.RoleName,
+					AdoptExisting:            types.BoolValue(priorState.AdoptExisting.ValueBool()),
+					Adopted:                  types.BoolValue(priorState.Adopted.ValueBool()),
+					RetainOnDestroy:          types.BoolValue(priorState.RetainOnDestroy.ValueBool()),
+					DeletionProtection:       types.BoolValue(priorState.DeletionProtection.ValueBool()),
+				}
+				upgradedState.setIdentifiers(priorState.ID.ValueInt64(), priorState.Client.ValueInt64(), priorState.AccountNumber.ValueString())
+				resp.Diagnostics.AddWarning(clientDeprecationSummary, clientDeprecationDetail)
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, upgradedState)...)
+			},
 		},
+		1: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id":                          schema.Int64Attribute{Computed: true},
+					"last_updated":                schema.StringAttribute{Computed: true},
+					"created_at":                  schema.StringAttribute{Computed: true},
+					"updated_at":                  schema.StringAttribute{Computed: true},
+					"integrated_at":               schema.StringAttribute{Computed: true},
+					"name":                        schema.StringAttribute{Required: true},
+					"account_number":              schema.StringAttribute{Required: true},
+					"role_name":                   schema.StringAttribute{Computed: true},
+					"master_payer_account_number": schema.StringAttribute{Required: true},
+					"client":                      schema.Int64Attribute{Computed: true},
+					"arn":                         schema.StringAttribute{Computed: true},
+					"bucket":                      schema.StringAttribute{Computed: true},
+					"external_id":                 schema.StringAttribute{Computed: true, Sensitive: true},
+					"adopt_existing":              schema.BoolAttribute{Optional: true, Computed: true},
+					"adopted":                     schema.BoolAttribute{Computed: true},
+					"retain_on_destroy":           schema.BoolAttribute{Optional: true, Computed: true},
+					"deletion_protection":         schema.BoolAttribute{Optional: true, Computed: true},
+				},
+			},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var priorState projectModelV1
+				resp.Diagnostics.Append(req.State.Get(ctx, &priorState)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				upgradedState := ProjectModel{
+					ID:                       priorState.ID,
+					LastUpdated:              priorState.LastUpdated,
+					Name:                     priorState.Name,
+					AccountNumber:            priorState.AccountNumber,
+					MasterPayerAccountNumber: priorState.MasterPayerAccountNumber,
+					Arn:                      priorState.Arn,
+					Bucket:                   priorState.Bucket,
+					Client:                   priorState.Client,
+					ExternalID:               priorState.ExternalID,
+					RoleName:                 priorState.RoleName,
+					AdoptExisting:            priorState.AdoptExisting,
+					Adopted:                  priorState.Adopted,
+					RetainOnDestroy:          priorState.RetainOnDestroy,
+					DeletionProtection:       priorState.DeletionProtection,
+					CreatedAt:                priorState.CreatedAt,
+					UpdatedAt:                priorState.UpdatedAt,
+					IntegratedAt:             priorState.IntegratedAt,
+				}
+				upgradedState.setIdentifiers(priorState.ID.ValueInt64(), priorState.Client.ValueInt64(), priorState.AccountNumber.ValueString())
+				resp.Diagnostics.AddWarning(clientDeprecationSummary, clientDeprecationDetail)
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, upgradedState)...)
+			},
+		},
+	}
+}
+
+// ModifyPlan plans whether the account is a payer and validates the payer of linked accounts is onboarded to nOps,
+// linked accounts can't be onboarded under a payer nOps doesn't know about.
+func (r *projectResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to validate on destroy or before the provider is configured.
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var plan ProjectModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	var payerProjectID types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("payer_project_id"), &payerProjectID)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.AccountNumber.IsUnknown() || plan.MasterPayerAccountNumber.IsUnknown() {
+		return
+	}
+
+	plan.IsPayer = types.BoolValue(plan.AccountNumber.Equal(plan.MasterPayerAccountNumber))
+	if plan.IsPayer.ValueBool() {
+		if !payerProjectID.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("payer_project_id"),
+				"Invalid payer project",
+				fmt.Sprintf("Account %s is a master payer account, payer_project_id can only be set for linked accounts.", plan.AccountNumber.ValueString()),
+			)
+			return
+		}
+		plan.PayerProjectID = plan.ID
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+		return
+	}
+
+	// The payer of existing linked accounts is only validated again when it changes.
+	if !req.State.Raw.IsNull() {
+		var state ProjectModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if state.IsPayer.Equal(plan.IsPayer) && state.MasterPayerAccountNumber.Equal(plan.MasterPayerAccountNumber) &&
+			(payerProjectID.IsNull() || payerProjectID.Equal(state.PayerProjectID)) {
+			resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+			return
+		}
+	}
+
+	// The payer is onboarded in the same apply, it is validated on create.
+	if payerProjectID.IsUnknown() {
+		plan.PayerProjectID = types.Int64Unknown()
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+		return
+	}
+
+	projects, err := r.client.GetProjects(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote project data",
+			err.Error(),
+		)
+		return
+	}
+
+	payer, err := lookupPayerProject(projects, plan.MasterPayerAccountNumber.ValueString(), payerProjectID)
+	if err != nil {
+		attribute := path.Root("master_payer_account_number")
+		if !payerProjectID.IsNull() {
+			attribute = path.Root("payer_project_id")
+		}
+		resp.Diagnostics.AddAttributeError(attribute, "Unknown master payer account", err.Error())
+		return
 	}
+
+	plan.PayerProjectID = types.Int64Value(int64(payer.ID))
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -128,7 +465,7 @@ func (r *projectResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	projects, err := r.client.GetProjects()
+	projects, err := r.client.GetProjects(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error: Error getting remote project data",
@@ -137,36 +474,63 @@ func (r *projectResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	for _, project := range projects {
-		if types.StringValue(project.AccountNumber) == plan.AccountNumber && project.RoleName != "na" {
+	// Linked accounts can only be onboarded once their payer is, it can be onboarded earlier in the same apply.
+	var payer *Project
+	if !plan.AccountNumber.Equal(plan.MasterPayerAccountNumber) {
+		payer, err = lookupPayerProject(projects, plan.MasterPayerAccountNumber.ValueString(), plan.PayerProjectID)
+		if err != nil {
+			resp.Diagnostics.AddError("Error: unknown master payer account", err.Error())
+			return
+		}
+	}
+
+	if project, pending := lookupAccountProject(projects, plan.AccountNumber.ValueString()); project != nil {
+		if !pending && !plan.AdoptExisting.ValueBool() {
 			// Check if the project has already been onboarded for this AWS account and has a role assigned(finished being integrated)
 			resp.Diagnostics.AddError(
 				fmt.Sprintf("Error: a project already exists for this AWS account %s with ID %d, please review or import by following this documentation: https://help.nops.io/docs/getting-started/Onboarding/onboarding-aws-with-terraform/#importing-existing-nops-projects", plan.AccountNumber, project.ID),
-				fmt.Sprintf("Project found for AWS account %s", plan.AccountNumber),
+				fmt.Sprintf("Project found for AWS account %s, set adopt_existing to take ownership of it", plan.AccountNumber),
 			)
 			return
 		}
 
-		if types.StringValue(project.AccountNumber) == plan.AccountNumber && project.RoleName == "na" {
-			// Check if the project was auto discovered by the backend. If it was, skip upstream creation and just save values to plan
-			tflog.Debug(ctx, fmt.Sprintf("Project %d pending integration found, skipping project creation and saving current values to state", project.ID))
-			plan.ID = types.Int64Value(int64(project.ID))
-			plan.Client = types.Int64Value(int64(project.Client))
-			plan.Arn = types.StringValue(project.Arn)
-			plan.Bucket = types.StringValue(project.Bucket)
-			plan.AccountNumber = types.StringValue(project.AccountNumber)
-			plan.ExternalID = types.StringValue(project.ExternalID)
-			plan.RoleName = types.StringValue(project.RoleName)
-			plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
-
-			// Set state to fully populated data
-			diags = resp.State.Set(ctx, plan)
-			resp.Diagnostics.Append(diags...)
-			if resp.Diagnostics.HasError() {
+		// The project was either auto discovered by the backend or is being adopted. Skip upstream creation and just save values to plan
+		tflog.Debug(ctx, fmt.Sprintf("Project %d found for account %s, skipping project creation and saving current values to state", project.ID, project.AccountNumber))
+		if project.Name != plan.Name.ValueString() {
+			project, err = r.client.UpdateProject(ctx, int64(project.ID), UpdateProject{
+				Name:          plan.Name.ValueString(),
+				AccountNumber: project.AccountNumber,
+			})
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error updating adopted project",
+					"Could not reconcile the name of the existing project, unexpected error: "+err.Error(),
+				)
 				return
 			}
+		}
+		plan.ID = types.Int64Value(int64(project.ID))
+		plan.Client = types.Int64Value(int64(project.Client))
+		plan.Arn = types.StringValue(project.Arn)
+		plan.Bucket = types.StringValue(project.Bucket)
+		plan.AccountNumber = types.StringValue(project.AccountNumber)
+		plan.ExternalID = types.StringValue(project.ExternalID)
+		plan.RoleName = types.StringValue(project.RoleName)
+		plan.Adopted = types.BoolValue(true)
+		plan.CreatedAt = serverTimestamp(project.CreatedAt)
+		plan.UpdatedAt = serverTimestamp(project.UpdatedAt)
+		plan.IntegratedAt = serverTimestamp(project.IntegratedAt)
+		plan.setIdentifiers(int64(project.ID), int64(project.Client), project.AccountNumber)
+		plan.setPayer(project, payer)
+		plan.LastUpdated = lastUpdated(project)
+
+		// Set state to fully populated data
+		diags = resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
 			return
 		}
+		return
 	}
 
 	// Create new project if its not upstream
@@ -174,7 +538,7 @@ func (r *projectResource) Create(ctx context.Context, req resource.CreateRequest
 	newProject.Name = plan.Name.ValueString()
 	newProject.AccountNumber = plan.AccountNumber.ValueString()
 	newProject.MasterPayerAccountNumber = plan.MasterPayerAccountNumber.ValueString()
-	project, err := r.client.CreateProject(newProject)
+	project, err := r.client.CreateProject(ctx, newProject)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating project",
@@ -192,7 +556,13 @@ func (r *projectResource) Create(ctx context.Context, req resource.CreateRequest
 	plan.AccountNumber = types.StringValue(project.AccountNumber)
 	plan.ExternalID = types.StringValue(project.ExternalID)
 	plan.RoleName = types.StringValue(project.RoleName)
-	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+	plan.Adopted = types.BoolValue(false)
+	plan.CreatedAt = serverTimestamp(project.CreatedAt)
+	plan.UpdatedAt = serverTimestamp(project.UpdatedAt)
+	plan.IntegratedAt = serverTimestamp(project.IntegratedAt)
+	plan.setIdentifiers(int64(project.ID), int64(project.Client), project.AccountNumber)
+	plan.setPayer(project, payer)
+	plan.LastUpdated = lastUpdated(project)
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
@@ -211,7 +581,7 @@ func (r *projectResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	projects, err := r.client.GetProjects()
+	projects, err := r.client.GetProjects(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error getting remote project data",
@@ -224,20 +594,54 @@ func (r *projectResource) Read(ctx context.Context, req resource.ReadRequest, re
 	for _, project := range projects {
 		if types.Int64Value(int64(project.ID)) == state.ID {
 			existingProject = true
-			ctx = tflog.SetField(ctx, "project", project)
+			ctx = tflog.SetField(ctx, "project_id", project.ID)
 			tflog.Debug(ctx, "Upstream project data received for account number "+project.AccountNumber+" name: "+project.Name)
+			var drift driftDetector
 			state.ID = types.Int64Value(int64(project.ID))
 			state.Client = types.Int64Value(int64(project.Client))
-			state.Arn = types.StringValue(project.Arn)
-			state.Bucket = types.StringValue(project.Bucket)
-			state.ExternalID = types.StringValue(project.ExternalID)
-			state.RoleName = types.StringValue(project.RoleName)
+			state.Name = drift.refresh("name", state.Name, project.Name)
+			state.AccountNumber = drift.refresh("account_number", state.AccountNumber, project.AccountNumber)
+			state.Arn = drift.refresh("arn", state.Arn, project.Arn)
+			state.Bucket = drift.refresh("bucket", state.Bucket, project.Bucket)
+			state.ExternalID = drift.refreshSensitive("external_id", state.ExternalID, project.ExternalID)
+			state.RoleName = drift.refresh("role_name", state.RoleName, project.RoleName)
+			if project.MasterPayerAccountNumber != "" {
+				state.MasterPayerAccountNumber = drift.refresh("master_payer_account_number", state.MasterPayerAccountNumber, project.MasterPayerAccountNumber)
+			}
+			drift.report(ctx, &resp.Diagnostics, r.client.DriftReport, fmt.Sprintf("nOps project %d", project.ID), project.UpdatedBy, project.UpdatedAt)
+
+			// A newer update timestamp means the project was changed in nOps since Terraform last saw it.
+			if updatedAt := serverTimestamp(project.UpdatedAt); !state.UpdatedAt.IsNull() && !updatedAt.Equal(state.UpdatedAt) {
+				tflog.Info(ctx, fmt.Sprintf("Project %d was updated in nOps at %s outside of Terraform", project.ID, updatedAt.ValueString()))
+			}
+			state.CreatedAt = serverTimestamp(project.CreatedAt)
+			state.UpdatedAt = serverTimestamp(project.UpdatedAt)
+			state.IntegratedAt = serverTimestamp(project.IntegratedAt)
+			state.setIdentifiers(int64(project.ID), int64(project.Client), project.AccountNumber)
+			state.refreshPayer(projects, &project)
+			if project.UpdatedAt != "" {
+				state.LastUpdated = lastUpdated(&project)
+			}
 		}
 	}
 	if !existingProject {
 		resp.Diagnostics.AddError(fmt.Sprintf("Project %s wasn't found in nOps, please check or remove from state", state.ID.String()), "Project not found")
 	}
 
+	// Imported projects and states written by older provider versions don't have these attributes set.
+	if state.AdoptExisting.IsNull() {
+		state.AdoptExisting = types.BoolValue(false)
+	}
+	if state.Adopted.IsNull() {
+		state.Adopted = types.BoolValue(false)
+	}
+	if state.RetainOnDestroy.IsNull() {
+		state.RetainOnDestroy = types.BoolValue(false)
+	}
+	if state.DeletionProtection.IsNull() {
+		state.DeletionProtection = types.BoolValue(false)
+	}
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -276,7 +680,7 @@ func (r *projectResource) Update(ctx context.Context, req resource.UpdateRequest
 	updateProjectRequest.Name = plan.Name.ValueString()
 	updateProjectRequest.AccountNumber = plan.AccountNumber.ValueString()
 
-	project, err := r.client.UpdateProject(currentState.ID.ValueInt64(), updateProjectRequest)
+	project, err := r.client.UpdateProject(ctx, currentState.ID.ValueInt64(), updateProjectRequest)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating project",
@@ -286,7 +690,6 @@ func (r *projectResource) Update(ctx context.Context, req resource.UpdateRequest
 	}
 
 	// Set values to updated fields in nOps
-	ctx = tflog.SetField(ctx, "project_update", project)
 	tflog.Debug(ctx, fmt.Sprintf("Updated project data for project id %d, account number %s and name %s", project.ID, project.AccountNumber, project.Name))
 	plan.ID = types.Int64Value(int64(project.ID))
 	plan.Name = types.StringValue(project.Name)
@@ -296,7 +699,27 @@ func (r *projectResource) Update(ctx context.Context, req resource.UpdateRequest
 	plan.Bucket = types.StringValue(project.Bucket)
 	plan.ExternalID = types.StringValue(project.ExternalID)
 	plan.RoleName = types.StringValue(project.RoleName)
-	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+	plan.CreatedAt = serverTimestamp(project.CreatedAt)
+	plan.UpdatedAt = serverTimestamp(project.UpdatedAt)
+	plan.IntegratedAt = serverTimestamp(project.IntegratedAt)
+	plan.setIdentifiers(int64(project.ID), int64(project.Client), project.AccountNumber)
+	plan.LastUpdated = lastUpdated(project)
+
+	// The payer project is looked up again when it wasn't planned, such as when master_payer_account_number changed.
+	if plan.PayerProjectID.IsUnknown() {
+		projects, err := r.client.GetProjects(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error getting remote project data",
+				err.Error(),
+			)
+			return
+		}
+		plan.PayerProjectID = types.Int64Null()
+		plan.refreshPayer(projects, project)
+	} else {
+		plan.IsPayer = types.BoolValue(plan.AccountNumber.Equal(plan.MasterPayerAccountNumber))
+	}
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, plan)
@@ -316,7 +739,21 @@ func (r *projectResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
-	err := r.client.DeleteProject(state.ID.ValueInt64())
+	if state.DeletionProtection.ValueBool() {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Project %d is protected from deletion", state.ID.ValueInt64()),
+			"Deleting the project would delete its cost history in nOps. Set deletion_protection to false and apply before destroying it, "+
+				"or set retain_on_destroy to true to only remove it from the Terraform state.",
+		)
+		return
+	}
+
+	if state.RetainOnDestroy.ValueBool() {
+		tflog.Info(ctx, fmt.Sprintf("Retaining project %d in nOps, only removing it from state", state.ID.ValueInt64()))
+		return
+	}
+
+	err := r.client.DeleteProject(ctx, state.ID.ValueInt64())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting project",
@@ -326,3 +763,133 @@ func (r *projectResource) Delete(ctx context.Context, req resource.DeleteRequest
 	}
 
 }
+
+// setIdentifiers sets the identifiers of the project and the names derived from them.
+func (m *ProjectModel) setIdentifiers(projectID, clientID int64, accountNumber string) {
+	m.ProjectID = types.Int64Value(projectID)
+	m.ClientID = types.Int64Value(clientID)
+	m.IntegrationRoleName = types.StringValue(integrationRoleName(clientID))
+	m.SystemBucketName = types.StringValue(systemBucketName(clientID, projectID, accountNumber))
+}
+
+// setPayer sets whether the project is a payer and the identifier of its payer project, payer is nil for payer accounts.
+func (m *ProjectModel) setPayer(project *Project, payer *Project) {
+	m.IsPayer = types.BoolValue(payer == nil)
+	if payer == nil {
+		payer = project
+	}
+	m.PayerProjectID = types.Int64Value(int64(payer.ID))
+}
+
+// refreshPayer refreshes whether the project is a payer and the identifier of its payer project,
+// which is kept as it is when the payer isn't onboarded to nOps anymore.
+func (m *ProjectModel) refreshPayer(projects []Project, project *Project) {
+	m.IsPayer = types.BoolValue(m.AccountNumber.Equal(m.MasterPayerAccountNumber))
+	if m.IsPayer.ValueBool() {
+		m.PayerProjectID = types.Int64Value(int64(project.ID))
+		return
+	}
+	if payer, _ := lookupAccountProject(projects, m.MasterPayerAccountNumber.ValueString()); payer != nil {
+		m.PayerProjectID = types.Int64Value(int64(payer.ID))
+	}
+}
+
+// lookupPayerProject returns the project of the master payer account of a linked account, the project with payerProjectID
+// when it is set. The error explains how to onboard the payer when nOps doesn't know about it.
+func lookupPayerProject(projects []Project, payerAccountNumber string, payerProjectID types.Int64) (*Project, error) {
+	if !payerProjectID.IsNull() && !payerProjectID.IsUnknown() {
+		payer := lookupProject(projects, payerProjectID.ValueInt64())
+		if payer == nil {
+			return nil, fmt.Errorf("nOps project %d wasn't found, payer_project_id must be the project of the master payer account %s", payerProjectID.ValueInt64(), payerAccountNumber)
+		}
+		if payer.AccountNumber != payerAccountNumber {
+			return nil, fmt.Errorf("nOps project %d is for account %s, not the master payer account %s", payer.ID, payer.AccountNumber, payerAccountNumber)
+		}
+		return payer, nil
+	}
+
+	payer, _ := lookupAccountProject(projects, payerAccountNumber)
+	if payer == nil {
+		return nil, fmt.Errorf("master payer account %s isn't onboarded to nOps, linked accounts can only be onboarded under a known payer. "+
+			"Onboard the payer account first, or set payer_project_id to the id of its nops_project to onboard both in the same apply.", payerAccountNumber)
+	}
+
+	return payer, nil
+}
+
+// lookupProject returns the project with the ID, if any.
+func lookupProject(projects []Project, id int64) *Project {
+	for i := range projects {
+		if int64(projects[i].ID) == id {
+			return &projects[i]
+		}
+	}
+
+	return nil
+}
+
+// lookupPayerProjectByID returns the project with the ID, failing when it doesn't exist or nOps reports it as a linked account.
+// The projects API usually leaves out the master payer account, the linked accounts it doesn't report can't be told apart
+// from payers and are left to nOps to reject.
+func lookupPayerProjectByID(projects []Project, id int64) (*Project, error) {
+	project := lookupProject(projects, id)
+	if project == nil {
+		return nil, fmt.Errorf("no project with ID %d exists in nOps", id)
+	}
+	if project.MasterPayerAccountNumber != "" && project.MasterPayerAccountNumber != project.AccountNumber {
+		return nil, fmt.Errorf("nOps project %d is a linked account of payer %s, use the project of the payer account", project.ID, project.MasterPayerAccountNumber)
+	}
+
+	return project, nil
+}
+
+// lookupAccountProject returns the first project registered for the AWS account, if any, and whether
+// it is still pending integration, meaning it was auto discovered by the backend and has no role assigned yet.
+func lookupAccountProject(projects []Project, accountNumber string) (*Project, bool) {
+	for i := range projects {
+		if projects[i].AccountNumber == accountNumber {
+			return &projects[i], projects[i].RoleName == "na"
+		}
+	}
+
+	return nil, false
+}
+
+// upgradeLastUpdated converts a last_updated value written with time.RFC850 to time.RFC3339.
+// Values that can't be parsed are kept as they are, they are replaced on the next update.
+func upgradeLastUpdated(value types.String) types.String {
+	if value.IsNull() || value.IsUnknown() {
+		return value
+	}
+
+	lastUpdated, err := time.Parse(time.RFC850, value.ValueString())
+	if err != nil {
+		return value
+	}
+
+	return types.StringValue(lastUpdated.Format(time.RFC3339))
+}
+
+// serverTimestamp normalizes a timestamp returned by the nOps APIs to time.RFC3339, null when it isn't set.
+func serverTimestamp(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return types.StringValue(value)
+	}
+
+	return types.StringValue(timestamp.UTC().Format(time.RFC3339))
+}
+
+// lastUpdated derives last_updated from the time nOps last updated the project,
+// falling back to the local clock when the API doesn't report it.
+func lastUpdated(project *Project) types.String {
+	if project.UpdatedAt == "" {
+		return types.StringValue(time.Now().Format(time.RFC3339))
+	}
+
+	return serverTimestamp(project.UpdatedAt)
+}
@@ -1,8 +1,14 @@
 package nops
 
 import (
+	"context"
+	"strings"
 	"testing"
 
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
@@ -45,3 +51,382 @@ resource "nops_project" "test" {
 		},
 	})
 }
+
+func testProjectModel(id int64) ProjectModel {
+	return ProjectModel{
+		ID:                       types.Int64Value(id),
+		Name:                     types.StringValue("automated-testing"),
+		AccountNumber:            types.StringValue("580010171808"),
+		MasterPayerAccountNumber: types.StringValue("580010171808"),
+		AdoptExisting:            types.BoolValue(false),
+		RetainOnDestroy:          types.BoolValue(false),
+		DeletionProtection:       types.BoolValue(false),
+	}
+}
+
+func TestProjectResourceDelete(t *testing.T) {
+	cases := map[string]struct {
+		retainOnDestroy    bool
+		deletionProtection bool
+		expectError        bool
+		expectDeleted      bool
+	}{
+		"delete": {
+			expectDeleted: true,
+		},
+		"retain_on_destroy": {
+			retainOnDestroy: true,
+		},
+		"deletion_protection": {
+			deletionProtection: true,
+			expectError:        true,
+		},
+		"deletion_protection wins over retain_on_destroy": {
+			retainOnDestroy:    true,
+			deletionProtection: true,
+			expectError:        true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fake := newFakeNops(Project{ID: 1, Name: "automated-testing", AccountNumber: "580010171808"})
+			r, s := newTestResource(t, fake, &projectResource{})
+
+			model := testProjectModel(1)
+			model.RetainOnDestroy = types.BoolValue(tc.retainOnDestroy)
+			model.DeletionProtection = types.BoolValue(tc.deletionProtection)
+			resp := fwresource.DeleteResponse{State: testResourceState(t, s, &model)}
+			r.Delete(context.Background(), fwresource.DeleteRequest{State: resp.State}, &resp)
+
+			if resp.Diagnostics.HasError() != tc.expectError {
+				t.Errorf("expected error %t, got diagnostics: %v", tc.expectError, resp.Diagnostics)
+			}
+			if _, ok := fake.project(1); ok == tc.expectDeleted {
+				t.Errorf("expected project deleted %t, found in nOps %t", tc.expectDeleted, ok)
+			}
+			if fake.requested("DELETE", "/c/admin/projectaws/1/") != tc.expectDeleted {
+				t.Errorf("expected delete request %t", tc.expectDeleted)
+			}
+		})
+	}
+}
+
+func TestProjectResourceCreateExisting(t *testing.T) {
+	cases := map[string]struct {
+		adoptExisting bool
+		expectError   bool
+	}{
+		"refused": {
+			expectError: true,
+		},
+		"adopt_existing": {
+			adoptExisting: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fake := newFakeNops(Project{ID: 7, Client: 15418, Name: "created-in-ui", AccountNumber: "580010171808", RoleName: "NopsIntegrationRole"})
+			r, s := newTestResource(t, fake, &projectResource{})
+
+			model := testProjectModel(0)
+			model.ID = types.Int64Unknown()
+			model.AdoptExisting = types.BoolValue(tc.adoptExisting)
+			plan := testResourceState(t, s, &model)
+			resp := fwresource.CreateResponse{State: nullResourceState(s)}
+			r.Create(context.Background(), fwresource.CreateRequest{Plan: tfsdk.Plan(plan)}, &resp)
+
+			if resp.Diagnostics.HasError() != tc.expectError {
+				t.Fatalf("expected error %t, got diagnostics: %v", tc.expectError, resp.Diagnostics)
+			}
+			if tc.expectError {
+				return
+			}
+
+			var state ProjectModel
+			resp.State.Get(context.Background(), &state)
+			if state.ID.ValueInt64() != 7 || !state.Adopted.ValueBool() {
+				t.Errorf("expected project 7 to be adopted, got ID %s adopted %s", state.ID, state.Adopted)
+			}
+			if project, _ := fake.project(7); project.Name != "automated-testing" {
+				t.Errorf("expected adopted project name to be reconciled, got %q", project.Name)
+			}
+			if fake.requested("POST", "/c/admin/projectaws/") {
+				t.Errorf("expected no project to be created")
+			}
+		})
+	}
+}
+
+func TestProjectResourceUpgradeStateV0(t *testing.T) {
+	ctx := context.Background()
+	r, s := newTestResource(t, newFakeNops(), &projectResource{})
+
+	upgrader := r.UpgradeState(ctx)[0]
+	priorSchema := *upgrader.PriorSchema
+	priorState := tfsdk.State{Schema: priorSchema, Raw: tftypes.NewValue(priorSchema.Type().TerraformType(ctx), nil)}
+	// States written by the first releases don't have the adoption and deletion attributes.
+	diags := priorState.Set(ctx, &projectModelV0{
+		ID:                       types.Int64Value(1),
+		LastUpdated:              types.StringValue("Monday, 21-Oct-24 15:04:05 UTC"),
+		Name:                     types.StringValue("automated-testing"),
+		AccountNumber:            types.StringValue("580010171808"),
+		MasterPayerAccountNumber: types.StringValue("580010171808"),
+		Arn:                      types.StringValue("arn:aws:iam::580010171808:role/na"),
+		Bucket:                   types.StringValue("na"),
+		Client:                   types.Int64Value(15418),
+		ExternalID:               types.StringValue("external-id"),
+		RoleName:                 types.StringValue("na"),
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected error building prior state: %v", diags)
+	}
+
+	resp := fwresource.UpgradeStateResponse{State: nullResourceState(s)}
+	upgrader.StateUpgrader(ctx, fwresource.UpgradeStateRequest{State: &priorState}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error upgrading state: %v", resp.Diagnostics)
+	}
+
+	var state ProjectModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error reading upgraded state: %v", resp.Diagnostics)
+	}
+	if state.LastUpdated.ValueString() != "2024-10-21T15:04:05Z" {
+		t.Errorf("expected last_updated in RFC3339, got %s", state.LastUpdated)
+	}
+	if state.ID.ValueInt64() != 1 || state.Client.ValueInt64() != 15418 || state.ExternalID.ValueString() != "external-id" {
+		t.Errorf("expected prior values to be kept, got %+v", state)
+	}
+	if state.AdoptExisting.IsNull() || state.RetainOnDestroy.IsNull() || state.DeletionProtection.IsNull() || state.Adopted.IsNull() {
+		t.Errorf("expected missing attributes to default to false, got %+v", state)
+	}
+}
+
+func TestProjectResourceUpgradeStateV1(t *testing.T) {
+	ctx := context.Background()
+	r, s := newTestResource(t, newFakeNops(), &projectResource{})
+
+	upgrader := r.UpgradeState(ctx)[1]
+	priorSchema := *upgrader.PriorSchema
+	priorState := tfsdk.State{Schema: priorSchema, Raw: tftypes.NewValue(priorSchema.Type().TerraformType(ctx), nil)}
+	diags := priorState.Set(ctx, &projectModelV1{
+		ID:                       types.Int64Value(42),
+		LastUpdated:              types.StringValue("2024-10-21T15:04:05Z"),
+		Name:                     types.StringValue("automated-testing"),
+		AccountNumber:            types.StringValue("580010171808"),
+		MasterPayerAccountNumber: types.StringValue("580010171808"),
+		Client:                   types.Int64Value(15418),
+		AdoptExisting:            types.BoolValue(false),
+		Adopted:                  types.BoolValue(false),
+		RetainOnDestroy:          types.BoolValue(true),
+		DeletionProtection:       types.BoolValue(false),
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected error building prior state: %v", diags)
+	}
+
+	resp := fwresource.UpgradeStateResponse{State: nullResourceState(s)}
+	upgrader.StateUpgrader(ctx, fwresource.UpgradeStateRequest{State: &priorState}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error upgrading state: %v", resp.Diagnostics)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("expected a deprecation warning for client, got %v", resp.Diagnostics)
+	}
+
+	var state ProjectModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if state.ProjectID.ValueInt64() != 42 || state.ClientID.ValueInt64() != 15418 || !state.RetainOnDestroy.ValueBool() {
+		t.Errorf("expected prior values to be kept and identifiers to be set, got %+v", state)
+	}
+	if state.IntegrationRoleName.ValueString() != "NopsIntegrationRole-15418" || state.SystemBucketName.ValueString() != "nops-15418-42-580010171808" {
+		t.Errorf("expected names derived from the client and project identifiers, got %s and %s", state.IntegrationRoleName, state.SystemBucketName)
+	}
+}
+
+func TestProjectResourceServerTimestamps(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops()
+	r, s := newTestResource(t, fake, &projectResource{})
+
+	model := testProjectModel(0)
+	model.ID = types.Int64Unknown()
+	resp := fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &model))}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error creating project: %v", resp.Diagnostics)
+	}
+
+	var state ProjectModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error reading state: %v", resp.Diagnostics)
+	}
+	for name, value := range map[string]types.String{"last_updated": state.LastUpdated, "created_at": state.CreatedAt, "updated_at": state.UpdatedAt} {
+		if value.ValueString() != "2024-10-21T15:04:05Z" {
+			t.Errorf("expected %s to be reported by nOps, got %s", name, value)
+		}
+	}
+	if !state.IntegratedAt.IsNull() {
+		t.Errorf("expected integrated_at to be null while the integration is pending, got %s", state.IntegratedAt)
+	}
+	if !state.ProjectID.Equal(state.ID) || state.ClientID.ValueInt64() != 1000 || state.IntegrationRoleName.ValueString() != "NopsIntegrationRole-1000" {
+		t.Errorf("expected identifiers reported by nOps, got %s, %s and %s", state.ProjectID, state.ClientID, state.IntegrationRoleName)
+	}
+}
+
+func TestProjectResourceReadDrift(t *testing.T) {
+	for _, driftReport := range []bool{false, true} {
+		fake := newFakeNops(Project{
+			ID:            1,
+			Name:          "renamed-in-ui",
+			AccountNumber: "580010171808",
+			Arn:           "arn:aws:iam::580010171808:role/na",
+			Bucket:        "na",
+			ExternalID:    "external-id",
+			RoleName:      "na",
+			UpdatedAt:     fakeNopsTimestamp,
+			UpdatedBy:     "ops@example.com",
+		})
+		r, s := newTestResource(t, fake, &projectResource{})
+		r.client.DriftReport = driftReport
+
+		model := testProjectModel(1)
+		model.Arn = types.StringValue("arn:aws:iam::580010171808:role/na")
+		model.Bucket = types.StringValue("na")
+		model.ExternalID = types.StringValue("external-id")
+		model.RoleName = types.StringValue("na")
+		resp := fwresource.ReadResponse{State: testResourceState(t, s, &model)}
+		r.Read(context.Background(), fwresource.ReadRequest{State: resp.State}, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected error reading project: %v", resp.Diagnostics)
+		}
+
+		var state ProjectModel
+		resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+		if state.Name.ValueString() != "renamed-in-ui" {
+			t.Errorf("expected name to be refreshed, got %s", state.Name)
+		}
+		if warnings := resp.Diagnostics.WarningsCount(); driftReport != (warnings == 1) {
+			t.Errorf("expected drift warning %t, got %d warnings", driftReport, warnings)
+		}
+		if driftReport && !strings.Contains(resp.Diagnostics.Warnings()[0].Detail(), `name: "automated-testing" -> "renamed-in-ui"`) {
+			t.Errorf("expected the warning to summarize the rename, got %s", resp.Diagnostics.Warnings()[0].Detail())
+		}
+	}
+}
+
+func TestProjectResourceModifyPlanPayer(t *testing.T) {
+	cases := map[string]struct {
+		accountNumber  string
+		payerProjectID types.Int64
+		expectPayer    bool
+		expectError    bool
+		expectPayerID  types.Int64
+	}{
+		"payer": {
+			accountNumber: "580010171808",
+			expectPayer:   true,
+			expectPayerID: types.Int64Unknown(),
+		},
+		"linked account": {
+			accountNumber: "471112641702",
+			expectPayerID: types.Int64Value(1),
+		},
+		"linked account with payer project": {
+			accountNumber:  "471112641702",
+			payerProjectID: types.Int64Value(1),
+			expectPayerID:  types.Int64Value(1),
+		},
+		"linked account with payer onboarded in the same apply": {
+			accountNumber:  "471112641702",
+			payerProjectID: types.Int64Unknown(),
+			expectPayerID:  types.Int64Unknown(),
+		},
+		"linked account under an unknown payer": {
+			accountNumber: "471112641703",
+			expectError:   true,
+		},
+		"linked account with another account project": {
+			accountNumber:  "471112641702",
+			payerProjectID: types.Int64Value(2),
+			expectError:    true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			fake := newFakeNops(
+				Project{ID: 1, Client: 1000, AccountNumber: "580010171808"},
+				Project{ID: 2, Client: 1000, AccountNumber: "471112641704"},
+			)
+			r, s := newTestResource(t, fake, &projectResource{})
+
+			model := testProjectModel(0)
+			model.ID = types.Int64Unknown()
+			model.AccountNumber = types.StringValue(tc.accountNumber)
+			if tc.accountNumber == "471112641703" {
+				model.MasterPayerAccountNumber = types.StringValue("471112641705")
+			}
+			model.PayerProjectID = tc.payerProjectID
+			resp := fwresource.ModifyPlanResponse{Plan: tfsdk.Plan(testResourceState(t, s, &model))}
+			r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{
+				Config: tfsdk.Config(testResourceState(t, s, &model)),
+				Plan:   resp.Plan,
+				State:  nullResourceState(s),
+			}, &resp)
+			if resp.Diagnostics.HasError() != tc.expectError {
+				t.Fatalf("expected error %t, got diagnostics: %v", tc.expectError, resp.Diagnostics)
+			}
+			if tc.expectError {
+				return
+			}
+
+			var plan ProjectModel
+			resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+			if plan.IsPayer.ValueBool() != tc.expectPayer || !plan.PayerProjectID.Equal(tc.expectPayerID) {
+				t.Errorf("expected payer %t with payer project %s, got %s and %s", tc.expectPayer, tc.expectPayerID, plan.IsPayer, plan.PayerProjectID)
+			}
+		})
+	}
+}
+
+func TestProjectResourceCreateLinkedAccount(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(Project{ID: 1, Client: 1000, AccountNumber: "580010171808", MasterPayerAccountNumber: "580010171808"})
+	r, s := newTestResource(t, fake, &projectResource{})
+
+	model := testProjectModel(0)
+	model.ID = types.Int64Unknown()
+	model.AccountNumber = types.StringValue("471112641702")
+	resp := fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &model))}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error creating project: %v", resp.Diagnostics)
+	}
+
+	var state ProjectModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if state.IsPayer.ValueBool() || state.PayerProjectID.ValueInt64() != 1 {
+		t.Errorf("expected a linked account of payer project 1, got %s and %s", state.IsPayer, state.PayerProjectID)
+	}
+	if project, _ := fake.project(int(state.ID.ValueInt64())); project.MasterPayerAccountNumber != "580010171808" {
+		t.Errorf("expected the master payer account to be sent to nOps, got %+v", project)
+	}
+
+	// The payer is validated again on apply, in case it was removed from nOps since the plan.
+	model.AccountNumber = types.StringValue("471112641703")
+	model.MasterPayerAccountNumber = types.StringValue("471112641705")
+	resp = fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &model))}, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("expected an error onboarding a linked account under an unknown payer")
+	}
+	if len(fake.projects) != 2 {
+		t.Errorf("expected no project to be created for the linked account")
+	}
+}
@@ -77,7 +77,7 @@ func (d *projectsDataSource) Schema(_ context.Context, _ datasource.SchemaReques
 func (d *projectsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state projectsDataSourceModel
 
-	projects, err := d.client.GetProjects()
+	projects, err := d.client.GetProjects(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error getting remote project data",
@@ -87,7 +87,7 @@ func (d *projectsDataSource) Read(ctx context.Context, req datasource.ReadReques
 	}
 
 	for _, project := range projects {
-		ctx = tflog.SetField(ctx, "project", project)
+		ctx = tflog.SetField(ctx, "project_id", project.ID)
 		tflog.Debug(ctx, "Got project data")
 		projectState := projectsModel{
 			ID:     types.Int64Value(int64(project.ID)),
@@ -0,0 +1,73 @@
+package nops
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &systemBucketNameFunction{}
+
+// NewSystemBucketNameFunction is a helper function to simplify the provider implementation.
+func NewSystemBucketNameFunction() function.Function {
+	return &systemBucketNameFunction{}
+}
+
+// systemBucketNameFunction is the function implementation.
+type systemBucketNameFunction struct{}
+
+// Metadata returns the function name.
+func (f *systemBucketNameFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "system_bucket_name"
+}
+
+// Definition defines the function parameters and return type.
+func (f *systemBucketNameFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Name of the nOps system bucket",
+		Description: "Returns the canonical name of the S3 bucket receiving the cost reports of a project, `nops-<client_id>-<project_id>-<account_id>`. " +
+			"The project identifier is part of the name so several projects of a client can be integrated from the same account.",
+		Parameters: []function.Parameter{
+			function.Int64Parameter{
+				Name:        "client_id",
+				Description: "nOps client identifier, `nops_project.client_id`",
+			},
+			function.Int64Parameter{
+				Name:        "project_id",
+				Description: "nOps project identifier, `nops_project.project_id`",
+			},
+			function.StringParameter{
+				Name:        "account_id",
+				Description: "AWS account ID hosting the bucket, usually the payer account",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run returns the system bucket name for the project.
+func (f *systemBucketNameFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var clientID, projectID int64
+	var accountID string
+	resp.Error = req.Arguments.Get(ctx, &clientID, &projectID, &accountID)
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(
+		validateIDArgument(0, "client_id", clientID),
+		validateIDArgument(1, "project_id", projectID),
+		validateAccountIDArgument(2, "account_id", accountID),
+	)
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, systemBucketName(clientID, projectID, accountID))
+}
+
+// systemBucketName returns the canonical name of the system bucket of a project.
+func systemBucketName(clientID, projectID int64, accountID string) string {
+	return fmt.Sprintf("nops-%d-%d-%s", clientID, projectID, accountID)
+}
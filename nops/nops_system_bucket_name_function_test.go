@@ -0,0 +1,50 @@
+package nops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSystemBucketNameFunction(t *testing.T) {
+	cases := map[string]struct {
+		clientID      int64
+		projectID     int64
+		accountID     string
+		expected      string
+		expectedError bool
+	}{
+		"project":         {clientID: 1000, projectID: 42, accountID: "580010171808", expected: "nops-1000-42-580010171808"},
+		"invalid project": {clientID: 1000, projectID: -1, accountID: "580010171808", expectedError: true},
+		"invalid account": {clientID: 1000, projectID: 42, accountID: "58001017", expectedError: true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			resp := function.RunResponse{Result: function.NewResultData(types.StringUnknown())}
+			NewSystemBucketNameFunction().Run(context.Background(), function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.Int64Value(c.clientID),
+					types.Int64Value(c.projectID),
+					types.StringValue(c.accountID),
+				}),
+			}, &resp)
+
+			if c.expectedError {
+				if resp.Error == nil {
+					t.Errorf("expected an error, got %s", resp.Result.Value())
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+			if !resp.Result.Value().Equal(types.StringValue(c.expected)) {
+				t.Errorf("expected %s, got %s", c.expected, resp.Result.Value())
+			}
+		})
+	}
+}
@@ -0,0 +1,455 @@
+package nops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &systemBucketResource{}
+	_ resource.ResourceWithConfigure   = &systemBucketResource{}
+	_ resource.ResourceWithImportState = &systemBucketResource{}
+	_ resource.ResourceWithModifyPlan  = &systemBucketResource{}
+)
+
+// defaultPartition is the AWS partition of the commercial regions.
+const defaultPartition = "aws"
+
+// Verification statuses reported by nOps for a system bucket.
+const (
+	systemBucketStatusVerified = "verified"
+	systemBucketStatusFailed   = "failed"
+)
+
+// systemBucketResource is the resource implementation.
+type systemBucketResource struct {
+	client *Client
+}
+
+type systemBucketModel struct {
+	ID                  types.Int64  `tfsdk:"id"`
+	LastUpdated         types.String `tfsdk:"last_updated"`
+	ProjectID           types.Int64  `tfsdk:"project_id"`
+	AccountID           types.String `tfsdk:"account_id"`
+	Partition           types.String `tfsdk:"partition"`
+	BucketName          types.String `tfsdk:"bucket_name"`
+	CanonicalBucketName types.String `tfsdk:"canonical_bucket_name"`
+	BucketPolicy        types.String `tfsdk:"bucket_policy"`
+	Verified            types.Bool   `tfsdk:"verified"`
+	Status              types.String `tfsdk:"status"`
+	StatusMessage       types.String `tfsdk:"status_message"`
+	VerifiedAt          types.String `tfsdk:"verified_at"`
+}
+
+// NewSystemBucketResource is a helper function to simplify the provider implementation.
+func NewSystemBucketResource() resource.Resource {
+	return &systemBucketResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *systemBucketResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *systemBucketResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_system_bucket"
+}
+
+// Schema defines the schema for the resource.
+func (r *systemBucketResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Registers the system bucket of a payer project with nOps, which verifies it can read the bucket through the integration role. " +
+			"The resource exposes the canonical bucket name and the bucket policy allowing AWS to deliver the cost reports, to be used by the S3 bucket resources.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Identifier of the system bucket, the nOps project identifier",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the resource was last updated",
+			},
+			"project_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "nOps project identifier of the payer account hosting the bucket, usually `nops_project.id`",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"account_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "AWS account ID hosting the bucket, the account of the project",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"partition": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(defaultPartition),
+				Description: "AWS partition of the account hosting the bucket, usually `data.aws_partition.current.partition`, defaults to `aws`",
+			},
+			"bucket_name": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Name of the S3 bucket to register, defaults to `canonical_bucket_name`. Only set it to keep a bucket created with another name",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"canonical_bucket_name": schema.StringAttribute{
+				Computed:    true,
+				Description: "Canonical name of the system bucket of the project, `nops-<client_id>-<project_id>-<account_id>`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"bucket_policy": schema.StringAttribute{
+				Computed: true,
+				Description: "JSON bucket policy to attach to the bucket, denying insecure transport and allowing AWS to deliver the " +
+					"Cost and Usage Reports and Data Exports of the account",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"verified": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether nOps verified it can read the bucket through the integration role",
+			},
+			"status": schema.StringAttribute{
+				Computed: true,
+				Description: "Verification status reported by nOps, `pending` until the bucket and the integration role are in place, " +
+					"then `verified` or `failed`",
+			},
+			"status_message": schema.StringAttribute{
+				Computed:    true,
+				Description: "Details about the verification status, explaining the failure when `status` is `failed`",
+			},
+			"verified_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when nOps last verified the bucket",
+			},
+		},
+	}
+}
+
+// ModifyPlan computes the bucket name and policy from the project when it already exists, so they are known
+// when planning the S3 bucket resources.
+func (r *systemBucketResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to compute on destroy, or when the project is created in the same apply.
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var plan systemBucketModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.ProjectID.IsUnknown() {
+		return
+	}
+
+	projects, err := r.client.GetProjects(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote project data",
+			err.Error(),
+		)
+		return
+	}
+	project := lookupProject(projects, plan.ProjectID.ValueInt64())
+	if project == nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("project_id"),
+			"Invalid nOps project",
+			fmt.Sprintf("No project with ID %d exists in nOps", plan.ProjectID.ValueInt64()),
+		)
+		return
+	}
+
+	var configuredBucketName types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("bucket_name"), &configuredBucketName)...)
+	plan.setProject(project)
+	if configuredBucketName.IsNull() {
+		plan.BucketName = plan.CanonicalBucketName
+	}
+	if !plan.BucketName.IsUnknown() {
+		plan.BucketPolicy, err = systemBucketPolicy(plan.Partition.ValueString(), plan.BucketName.ValueString(), project.AccountNumber)
+		if err != nil {
+			resp.Diagnostics.AddError("Error building bucket policy", err.Error())
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *systemBucketResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan systemBucketModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.register(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *systemBucketResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state systemBucketModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	bucket, err := r.client.GetSystemBucket(ctx, state.ID.ValueInt64())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, fmt.Sprintf("No system bucket registered in nOps for project %d, removing it from state", state.ID.ValueInt64()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote system bucket",
+			err.Error(),
+		)
+		return
+	}
+
+	// Imported buckets only have their identifier, the attributes derived from the project are filled once.
+	state.ProjectID = state.ID
+	if state.CanonicalBucketName.IsNull() {
+		projects, err := r.client.GetProjects(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error getting remote project data",
+				err.Error(),
+			)
+			return
+		}
+		if project := lookupProject(projects, state.ID.ValueInt64()); project != nil {
+			state.setProject(project)
+		}
+	}
+	if state.Partition.IsNull() {
+		state.Partition = types.StringValue(defaultPartition)
+	}
+	state.BucketName = types.StringValue(bucket.BucketName)
+	if !state.AccountID.IsNull() {
+		state.BucketPolicy, err = systemBucketPolicy(state.Partition.ValueString(), bucket.BucketName, state.AccountID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Error building bucket policy", err.Error())
+			return
+		}
+	}
+	state.refresh(bucket)
+	if bucket.Status == systemBucketStatusFailed {
+		resp.Diagnostics.AddWarning(
+			fmt.Sprintf("nOps can't read the system bucket %s", bucket.BucketName),
+			bucket.StatusMessage,
+		)
+	}
+	if state.LastUpdated.IsNull() {
+		state.LastUpdated = types.StringValue(time.Now().Format(time.RFC3339))
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *systemBucketResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The system bucket is imported with the identifier of its project.
+	val, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Error parsing ID for import, please check for a correct project ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), val)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *systemBucketResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan systemBucketModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Registering the bucket again makes nOps verify it again.
+	resp.Diagnostics.Append(r.register(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *systemBucketResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state systemBucketModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteSystemBucket(ctx, state.ID.ValueInt64())
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error unregistering system bucket",
+			err.Error(),
+		)
+		return
+	}
+}
+
+// register registers the planned bucket with nOps and sets the resulting attributes.
+func (r *systemBucketResource) register(ctx context.Context, plan *systemBucketModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	projects, err := r.client.GetProjects(ctx)
+	if err != nil {
+		diags.AddError(
+			"Error getting remote project data",
+			err.Error(),
+		)
+		return diags
+	}
+	project := lookupProject(projects, plan.ProjectID.ValueInt64())
+	if project == nil {
+		diags.AddAttributeError(
+			path.Root("project_id"),
+			"Invalid nOps project",
+			fmt.Sprintf("No project with ID %d exists in nOps", plan.ProjectID.ValueInt64()),
+		)
+		return diags
+	}
+	plan.setProject(project)
+	if plan.BucketName.IsNull() || plan.BucketName.IsUnknown() {
+		plan.BucketName = plan.CanonicalBucketName
+	}
+	plan.BucketPolicy, err = systemBucketPolicy(plan.Partition.ValueString(), plan.BucketName.ValueString(), project.AccountNumber)
+	if err != nil {
+		diags.AddError("Error building bucket policy", err.Error())
+		return diags
+	}
+
+	bucket, err := r.client.PutSystemBucket(ctx, plan.ProjectID.ValueInt64(), SystemBucket{BucketName: plan.BucketName.ValueString()})
+	if err != nil {
+		diags.AddError(
+			"Error registering system bucket",
+			fmt.Sprintf("nOps rejected the system bucket %s of project %d: %s", plan.BucketName.ValueString(), project.ID, err.Error()),
+		)
+		return diags
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("System bucket %s registered for project %d with status %s", bucket.BucketName, project.ID, bucket.Status))
+	plan.ID = plan.ProjectID
+	plan.refresh(bucket)
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC3339))
+
+	return diags
+}
+
+// setProject sets the attributes derived from the project.
+func (m *systemBucketModel) setProject(project *Project) {
+	m.AccountID = types.StringValue(project.AccountNumber)
+	m.CanonicalBucketName = types.StringValue(systemBucketName(int64(project.Client), int64(project.ID), project.AccountNumber))
+}
+
+// refresh sets the verification attributes reported by nOps.
+func (m *systemBucketModel) refresh(bucket *SystemBucket) {
+	m.Verified = types.BoolValue(bucket.Status == systemBucketStatusVerified)
+	m.Status = types.StringValue(bucket.Status)
+	m.StatusMessage = types.StringValue(bucket.StatusMessage)
+	m.VerifiedAt = serverTimestamp(bucket.VerifiedAt)
+}
+
+// systemBucketPolicy returns the bucket policy of the system bucket, denying insecure transport and allowing
+// the AWS billing services of the account to deliver the cost reports.
+func systemBucketPolicy(partition, bucketName, accountID string) (types.String, error) {
+	bucketArn := fmt.Sprintf("arn:%s:s3:::%s", partition, bucketName)
+	reportServices := map[string]any{"Service": []string{"billingreports.amazonaws.com", "bcm-data-exports.amazonaws.com"}}
+	sourceAccount := map[string]any{"StringEquals": map[string]string{"aws:SourceAccount": accountID}}
+
+	policy, err := json.Marshal(map[string]any{
+		"Version": "2012-10-17",
+		"Statement": []map[string]any{
+			{
+				"Sid":       "DenyInsecureTransport",
+				"Effect":    "Deny",
+				"Principal": "*",
+				"Action":    "s3:*",
+				"Resource":  []string{bucketArn, bucketArn + "/*"},
+				"Condition": map[string]any{"Bool": map[string]string{"aws:SecureTransport": "false"}},
+			},
+			{
+				"Sid":       "AllowCostReportsBucketCheck",
+				"Effect":    "Allow",
+				"Principal": reportServices,
+				"Action":    []string{"s3:GetBucketAcl", "s3:GetBucketPolicy"},
+				"Resource":  bucketArn,
+				"Condition": sourceAccount,
+			},
+			{
+				"Sid":       "AllowCostReportsDelivery",
+				"Effect":    "Allow",
+				"Principal": reportServices,
+				"Action":    "s3:PutObject",
+				"Resource":  bucketArn + "/*",
+				"Condition": sourceAccount,
+			},
+		},
+	})
+	if err != nil {
+		return types.StringNull(), err
+	}
+
+	return types.StringValue(string(policy)), nil
+}
@@ -0,0 +1,144 @@
+package nops
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testSystemBucketModel() systemBucketModel {
+	return systemBucketModel{
+		ID:                  types.Int64Unknown(),
+		LastUpdated:         types.StringUnknown(),
+		ProjectID:           types.Int64Value(42),
+		AccountID:           types.StringUnknown(),
+		Partition:           types.StringValue(defaultPartition),
+		BucketName:          types.StringUnknown(),
+		CanonicalBucketName: types.StringUnknown(),
+		BucketPolicy:        types.StringUnknown(),
+		Verified:            types.BoolUnknown(),
+		Status:              types.StringUnknown(),
+		StatusMessage:       types.StringUnknown(),
+		VerifiedAt:          types.StringUnknown(),
+	}
+}
+
+func TestSystemBucketResourceModifyPlan(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(Project{ID: 42, Client: 1000, AccountNumber: "580010171808"})
+	r, s := newTestResource(t, fake, &systemBucketResource{})
+
+	config := systemBucketModel{ProjectID: types.Int64Value(42)}
+	plan := testSystemBucketModel()
+	resp := fwresource.ModifyPlanResponse{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}
+	r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{
+		Config: tfsdk.Config(testResourceState(t, s, &config)),
+		Plan:   resp.Plan,
+	}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error modifying plan: %v", resp.Diagnostics)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if plan.BucketName.ValueString() != "nops-1000-42-580010171808" || !plan.BucketName.Equal(plan.CanonicalBucketName) {
+		t.Errorf("expected the canonical bucket name to be planned, got %s and %s", plan.BucketName, plan.CanonicalBucketName)
+	}
+	if plan.BucketPolicy.IsUnknown() || !json.Valid([]byte(plan.BucketPolicy.ValueString())) {
+		t.Errorf("expected the bucket policy to be planned, got %s", plan.BucketPolicy)
+	}
+
+	config.ProjectID = types.Int64Value(99)
+	plan = testSystemBucketModel()
+	plan.ProjectID = types.Int64Value(99)
+	resp = fwresource.ModifyPlanResponse{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}
+	r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{
+		Config: tfsdk.Config(testResourceState(t, s, &config)),
+		Plan:   resp.Plan,
+	}, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("expected an error for a project that doesn't exist")
+	}
+}
+
+func TestSystemBucketResourceCreate(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(Project{ID: 42, Client: 1000, AccountNumber: "580010171808"})
+	r, s := newTestResource(t, fake, &systemBucketResource{})
+
+	plan := testSystemBucketModel()
+	plan.BucketName = types.StringValue("nops-legacy-bucket")
+	resp := fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error creating system bucket: %v", resp.Diagnostics)
+	}
+
+	var state systemBucketModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if state.BucketName.ValueString() != "nops-legacy-bucket" || state.CanonicalBucketName.ValueString() != "nops-1000-42-580010171808" {
+		t.Errorf("expected the configured bucket to be registered, got %s and %s", state.BucketName, state.CanonicalBucketName)
+	}
+	if !state.Verified.ValueBool() || state.VerifiedAt.ValueString() != "2024-10-21T15:04:05Z" {
+		t.Errorf("expected the bucket to be verified, got %s at %s", state.Status, state.VerifiedAt)
+	}
+	if bucket := fake.systemBuckets[42]; bucket.BucketName != "nops-legacy-bucket" {
+		t.Errorf("expected the bucket to be registered in nOps, got %+v", bucket)
+	}
+}
+
+func TestSystemBucketResourceReadFailed(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(Project{ID: 42, Client: 1000, AccountNumber: "580010171808"})
+	fake.systemBuckets[42] = SystemBucket{BucketName: "nops-1000-42-580010171808", Status: systemBucketStatusFailed, StatusMessage: "Access denied"}
+	r, s := newTestResource(t, fake, &systemBucketResource{})
+
+	// Imported system buckets only have their identifier in state.
+	state := nullResourceState(s)
+	state.SetAttribute(ctx, path.Root("id"), int64(42))
+	resp := fwresource.ReadResponse{State: state}
+	r.Read(ctx, fwresource.ReadRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error reading system bucket: %v", resp.Diagnostics)
+	}
+
+	var model systemBucketModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &model)...)
+	if model.Verified.ValueBool() || model.ProjectID.ValueInt64() != 42 || model.CanonicalBucketName.ValueString() != "nops-1000-42-580010171808" {
+		t.Errorf("expected an unverified bucket with its project attributes, got %+v", model)
+	}
+	if model.BucketPolicy.IsNull() {
+		t.Errorf("expected the bucket policy to be set")
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("expected a warning about the failed verification, got %v", resp.Diagnostics)
+	}
+}
+
+func TestSystemBucketPolicy(t *testing.T) {
+	policy, err := systemBucketPolicy("aws-us-gov", "nops-1000-42-580010171808", "580010171808")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var document struct {
+		Statement []struct {
+			Sid       string
+			Resource  any
+			Condition map[string]map[string]string
+		}
+	}
+	if err := json.Unmarshal([]byte(policy.ValueString()), &document); err != nil {
+		t.Fatalf("unexpected invalid policy: %s", err)
+	}
+	if len(document.Statement) != 3 {
+		t.Fatalf("expected 3 statements, got %d", len(document.Statement))
+	}
+	if delivery := document.Statement[2]; delivery.Resource != "arn:aws-us-gov:s3:::nops-1000-42-580010171808/*" || delivery.Condition["StringEquals"]["aws:SourceAccount"] != "580010171808" {
+		t.Errorf("expected report delivery to be restricted to the account, got %+v", delivery)
+	}
+}
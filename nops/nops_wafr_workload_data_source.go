@@ -0,0 +1,159 @@
+package nops
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &wafrWorkloadDataSource{}
+	_ datasource.DataSourceWithConfigure = &wafrWorkloadDataSource{}
+)
+
+func NewWAFRWorkloadDataSource() datasource.DataSource {
+	return &wafrWorkloadDataSource{}
+}
+
+// Data source implementation.
+type wafrWorkloadDataSource struct {
+	client *Client
+}
+
+type wafrWorkloadDataSourceModel struct {
+	ID              types.Int64  `tfsdk:"id"`
+	ProjectID       types.Int64  `tfsdk:"project_id"`
+	Name            types.String `tfsdk:"name"`
+	Environment     types.String `tfsdk:"environment"`
+	Regions         types.Set    `tfsdk:"regions"`
+	Lenses          types.Set    `tfsdk:"lenses"`
+	Owners          types.Set    `tfsdk:"owners"`
+	WorkloadArn     types.String `tfsdk:"workload_arn"`
+	HighRiskCount   types.Int64  `tfsdk:"high_risk_count"`
+	MediumRiskCount types.Int64  `tfsdk:"medium_risk_count"`
+	LastReviewedAt  types.String `tfsdk:"last_reviewed_at"`
+}
+
+// Metadata returns the data source type name.
+func (d *wafrWorkloadDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_wafr_workload"
+}
+
+// Schema defines the schema for the data source.
+func (d *wafrWorkloadDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "The WAFR workload datasource retrieves a Well-Architected workload of a project with the high and medium risks nOps found " +
+			"in its latest review. Use it to gate changes on the Well-Architected status of a workload.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Required:    true,
+				Description: "nOps identifier of the workload, usually `nops_wafr_workload.id`",
+			},
+			"project_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "nOps project identifier of the workload",
+			},
+			"name": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the workload",
+			},
+			"environment": schema.StringAttribute{
+				Computed:    true,
+				Description: "Environment of the workload, `production` or `preproduction`",
+			},
+			"regions": schema.SetAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "AWS regions the workload runs in",
+			},
+			"lenses": schema.SetAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Lenses the workload is reviewed with",
+			},
+			"owners": schema.SetAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Emails of the nOps users owning the review of the workload",
+			},
+			"workload_arn": schema.StringAttribute{
+				Computed:    true,
+				Description: "ARN of the workload in the Well-Architected Tool",
+			},
+			"high_risk_count": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of high risks found in the latest review of the workload",
+			},
+			"medium_risk_count": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of medium risks found in the latest review of the workload",
+			},
+			"last_reviewed_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp of the latest review of the workload, null until nOps reviewed it",
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *wafrWorkloadDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state wafrWorkloadDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workload, err := d.client.GetWAFRWorkload(ctx, state.ProjectID.ValueInt64(), state.ID.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote Well-Architected workload data",
+			fmt.Sprintf("Could not read workload %d of project %d: %s", state.ID.ValueInt64(), state.ProjectID.ValueInt64(), err.Error()),
+		)
+		return
+	}
+
+	state.Name = types.StringValue(workload.Name)
+	state.Environment = types.StringValue(workload.Environment)
+	var diags diag.Diagnostics
+	state.Regions, diags = types.SetValueFrom(ctx, types.StringType, workload.Regions)
+	resp.Diagnostics.Append(diags...)
+	state.Lenses, diags = types.SetValueFrom(ctx, types.StringType, workload.Lenses)
+	resp.Diagnostics.Append(diags...)
+	state.Owners, diags = types.SetValueFrom(ctx, types.StringType, workload.Owners)
+	resp.Diagnostics.Append(diags...)
+	state.WorkloadArn = types.StringValue(workload.WorkloadArn)
+	state.HighRiskCount = types.Int64Value(workload.HighRiskCount)
+	state.MediumRiskCount = types.Int64Value(workload.MediumRiskCount)
+	state.LastReviewedAt = serverTimestamp(workload.LastReviewedAt)
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *wafrWorkloadDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
@@ -0,0 +1,73 @@
+package nops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// readWAFRWorkloadDataSource reads the WAFR workload data source configured with the project and workload identifiers.
+func readWAFRWorkloadDataSource(t *testing.T, fake *fakeNops, projectID, workloadID int64) (wafrWorkloadDataSourceModel, datasource.ReadResponse) {
+	t.Helper()
+	ctx := context.Background()
+
+	d := &wafrWorkloadDataSource{client: fake.client(t)}
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+
+	config := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+	model := wafrWorkloadDataSourceModel{
+		ID:        types.Int64Value(workloadID),
+		ProjectID: types.Int64Value(projectID),
+		Regions:   types.SetNull(types.StringType),
+		Lenses:    types.SetNull(types.StringType),
+		Owners:    types.SetNull(types.StringType),
+	}
+	if diags := config.Set(ctx, &model); diags.HasError() {
+		t.Fatalf("unexpected error building config: %v", diags)
+	}
+
+	resp := datasource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: config.Raw}}
+	d.Read(ctx, datasource.ReadRequest{Config: tfsdk.Config(config)}, &resp)
+	var state wafrWorkloadDataSourceModel
+	if !resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	}
+
+	return state, resp
+}
+
+func TestWAFRWorkloadDataSourceRead(t *testing.T) {
+	fake := newFakeNops(Project{ID: 1, AccountNumber: "580010171808"})
+	fake.wafrWorkloads[7] = WAFRWorkload{
+		ID:              7,
+		Project:         1,
+		Name:            "checkout",
+		Environment:     wafrEnvironmentProduction,
+		Regions:         []string{"us-east-1"},
+		Lenses:          []string{wafrDefaultLens},
+		Owners:          []string{"platform@example.com"},
+		HighRiskCount:   2,
+		MediumRiskCount: 5,
+		LastReviewedAt:  fakeNopsTimestamp,
+	}
+
+	state, resp := readWAFRWorkloadDataSource(t, fake, 1, 7)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error reading WAFR workload: %v", resp.Diagnostics)
+	}
+	if state.HighRiskCount.ValueInt64() != 2 || state.MediumRiskCount.ValueInt64() != 5 {
+		t.Errorf("expected 2 high and 5 medium risks, got %s and %s", state.HighRiskCount, state.MediumRiskCount)
+	}
+	if state.Name.ValueString() != "checkout" || state.LastReviewedAt.ValueString() != "2024-10-21T15:04:05Z" {
+		t.Errorf("expected the reviewed checkout workload, got %s reviewed at %s", state.Name, state.LastReviewedAt)
+	}
+
+	if _, resp := readWAFRWorkloadDataSource(t, fake, 1, 8); !resp.Diagnostics.HasError() {
+		t.Errorf("expected an error reading a workload that doesn't exist")
+	}
+}
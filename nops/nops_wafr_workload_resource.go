@@ -0,0 +1,372 @@
+package nops
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &wafrWorkloadResource{}
+	_ resource.ResourceWithConfigure      = &wafrWorkloadResource{}
+	_ resource.ResourceWithImportState    = &wafrWorkloadResource{}
+	_ resource.ResourceWithValidateConfig = &wafrWorkloadResource{}
+)
+
+// Environments of the Well-Architected workloads.
+const (
+	wafrEnvironmentProduction    = "production"
+	wafrEnvironmentPreproduction = "preproduction"
+)
+
+// wafrDefaultLens is the alias of the AWS Well-Architected Framework lens, every workload is reviewed with it by default.
+const wafrDefaultLens = "wellarchitected"
+
+var (
+	// wafrWorkloadNamePattern matches the Well-Architected Tool workload naming rules.
+	wafrWorkloadNamePattern = regexp.MustCompile(`^[^\x00-\x1f]{3,100}$`)
+	// wafrLensPattern matches the lens aliases, such as serverless, and the ARNs of custom or catalog lenses.
+	wafrLensPattern = regexp.MustCompile(`^([a-z0-9]{1,64}|arn:aws[a-z-]*:wellarchitected:[a-z0-9-]*:\d{0,12}:lens/[A-Za-z0-9._-]{1,64})$`)
+	// emailPattern loosely matches an email address, nOps validates the owners are users of the client.
+	emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+)
+
+// wafrWorkloadResource is the resource implementation.
+type wafrWorkloadResource struct {
+	client *Client
+}
+
+type wafrWorkloadModel struct {
+	ID          types.Int64  `tfsdk:"id"`
+	LastUpdated types.String `tfsdk:"last_updated"`
+	ProjectID   types.Int64  `tfsdk:"project_id"`
+	Name        types.String `tfsdk:"name"`
+	Environment types.String `tfsdk:"environment"`
+	Regions     types.Set    `tfsdk:"regions"`
+	Lenses      types.Set    `tfsdk:"lenses"`
+	Owners      types.Set    `tfsdk:"owners"`
+	WorkloadArn types.String `tfsdk:"workload_arn"`
+}
+
+// NewWAFRWorkloadResource is a helper function to simplify the provider implementation.
+func NewWAFRWorkloadResource() resource.Resource {
+	return &wafrWorkloadResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *wafrWorkloadResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *wafrWorkloadResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_wafr_workload"
+}
+
+// Schema defines the schema for the resource.
+func (r *wafrWorkloadResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Creates a Well-Architected workload for a nOps project. nOps defines the workload in the Well-Architected Tool of the account " +
+			"and reviews it, use the `nops_wafr_workload` data source to read the risks it found. The project role needs the `NopsWAFRPolicy` policy.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "nOps identifier of the workload",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the resource was last updated, as reported by nOps",
+			},
+			"project_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "nOps project identifier of the AWS account running the workload, usually `nops_project.id`",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the workload, unique in the account",
+			},
+			"environment": schema.StringAttribute{
+				Required:    true,
+				Description: "Environment of the workload, `production` or `preproduction`",
+			},
+			"regions": schema.SetAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				Description: "AWS regions the workload runs in",
+			},
+			"lenses": schema.SetAttribute{
+				Optional:    true,
+				Computed:    true,
+				ElementType: types.StringType,
+				Default:     setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{types.StringValue(wafrDefaultLens)})),
+				Description: "Lenses the workload is reviewed with, as lens aliases such as `serverless` or lens ARNs. Defaults to the `wellarchitected` lens",
+			},
+			"owners": schema.SetAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				Description: "Emails of the nOps users owning the review of the workload",
+			},
+			"workload_arn": schema.StringAttribute{
+				Computed:    true,
+				Description: "ARN of the workload in the Well-Architected Tool",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// ValidateConfig validates the workload follows the Well-Architected Tool rules.
+func (r *wafrWorkloadResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config wafrWorkloadModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Name.IsNull() && !config.Name.IsUnknown() && !wafrWorkloadNamePattern.MatchString(config.Name.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("name"),
+			"Invalid workload name",
+			fmt.Sprintf("%q is not a valid workload name, it must be 3 to 100 characters", config.Name.ValueString()),
+		)
+	}
+
+	if environment := config.Environment; !environment.IsNull() && !environment.IsUnknown() &&
+		environment.ValueString() != wafrEnvironmentProduction && environment.ValueString() != wafrEnvironmentPreproduction {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("environment"),
+			"Invalid workload environment",
+			fmt.Sprintf("%q is not supported, expected %s or %s", environment.ValueString(), wafrEnvironmentProduction, wafrEnvironmentPreproduction),
+		)
+	}
+
+	for _, check := range []struct {
+		attribute string
+		value     types.Set
+		pattern   *regexp.Regexp
+		summary   string
+	}{
+		{"regions", config.Regions, awsRegionPattern, "Invalid workload region"},
+		{"lenses", config.Lenses, wafrLensPattern, "Invalid workload lens"},
+		{"owners", config.Owners, emailPattern, "Invalid workload owner"},
+	} {
+		if check.value.IsNull() || check.value.IsUnknown() {
+			continue
+		}
+		var values []types.String
+		resp.Diagnostics.Append(check.value.ElementsAs(ctx, &values, false)...)
+		if len(values) == 0 {
+			resp.Diagnostics.AddAttributeError(path.Root(check.attribute), check.summary, fmt.Sprintf("At least one of %s must be set", check.attribute))
+		}
+		for _, value := range values {
+			if !value.IsUnknown() && !check.pattern.MatchString(value.ValueString()) {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(check.attribute),
+					check.summary,
+					fmt.Sprintf("%q is not valid, expected it to match %s", value.ValueString(), check.pattern),
+				)
+			}
+		}
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *wafrWorkloadResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan wafrWorkloadModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	newWorkload, diags := plan.workload(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workload, err := r.client.CreateWAFRWorkload(ctx, plan.ProjectID.ValueInt64(), newWorkload)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating Well-Architected workload",
+			fmt.Sprintf("Could not create workload %s for project %d: %s", plan.Name.ValueString(), plan.ProjectID.ValueInt64(), err.Error()),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("Well-Architected workload %s created for project %d as %d", workload.Name, plan.ProjectID.ValueInt64(), workload.ID))
+	plan.refresh(workload)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *wafrWorkloadResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state wafrWorkloadModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workload, err := r.client.GetWAFRWorkload(ctx, state.ProjectID.ValueInt64(), state.ID.ValueInt64())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, fmt.Sprintf("Well-Architected workload %d doesn't exist in nOps anymore, removing it from state", state.ID.ValueInt64()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting remote Well-Architected workload data",
+			err.Error(),
+		)
+		return
+	}
+
+	state.Name = types.StringValue(workload.Name)
+	state.Environment = types.StringValue(workload.Environment)
+	var d diag.Diagnostics
+	state.Regions, d = types.SetValueFrom(ctx, types.StringType, workload.Regions)
+	resp.Diagnostics.Append(d...)
+	state.Lenses, d = types.SetValueFrom(ctx, types.StringType, workload.Lenses)
+	resp.Diagnostics.Append(d...)
+	state.Owners, d = types.SetValueFrom(ctx, types.StringType, workload.Owners)
+	resp.Diagnostics.Append(d...)
+	state.refresh(workload)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *wafrWorkloadResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Workloads are imported with the identifiers of their project and of the workload, <project_id>/<id>.
+	project, workload, err := parseProjectChildID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error parsing ID for import, please check for a correct <project_id>/<id> identifier",
+			fmt.Sprintf("Could not parse %q: %s", req.ID, err.Error()),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), project)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), workload)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *wafrWorkloadResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan wafrWorkloadModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	update, diags := plan.workload(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workload, err := r.client.UpdateWAFRWorkload(ctx, plan.ProjectID.ValueInt64(), plan.ID.ValueInt64(), update)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating Well-Architected workload",
+			fmt.Sprintf("Could not update workload %d of project %d: %s", plan.ID.ValueInt64(), plan.ProjectID.ValueInt64(), err.Error()),
+		)
+		return
+	}
+
+	plan.refresh(workload)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *wafrWorkloadResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state wafrWorkloadModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteWAFRWorkload(ctx, state.ProjectID.ValueInt64(), state.ID.ValueInt64())
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error deleting Well-Architected workload",
+			err.Error(),
+		)
+		return
+	}
+}
+
+// workload returns the nOps payload for the planned workload.
+func (m *wafrWorkloadModel) workload(ctx context.Context) (NewWAFRWorkload, diag.Diagnostics) {
+	workload := NewWAFRWorkload{
+		Name:        m.Name.ValueString(),
+		Environment: m.Environment.ValueString(),
+	}
+
+	var diags diag.Diagnostics
+	diags.Append(m.Regions.ElementsAs(ctx, &workload.Regions, false)...)
+	diags.Append(m.Lenses.ElementsAs(ctx, &workload.Lenses, false)...)
+	diags.Append(m.Owners.ElementsAs(ctx, &workload.Owners, false)...)
+	sort.Strings(workload.Regions)
+	sort.Strings(workload.Lenses)
+	sort.Strings(workload.Owners)
+
+	return workload, diags
+}
+
+// refresh sets the attributes computed by nOps.
+func (m *wafrWorkloadModel) refresh(workload *WAFRWorkload) {
+	m.ID = types.Int64Value(int64(workload.ID))
+	m.WorkloadArn = types.StringValue(workload.WorkloadArn)
+	m.LastUpdated = serverTimestamp(workload.UpdatedAt)
+	if m.LastUpdated.IsNull() {
+		m.LastUpdated = types.StringValue(time.Now().Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,137 @@
+package nops
+
+import (
+	"context"
+	"testing"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testWAFRWorkloadModel(t *testing.T) wafrWorkloadModel {
+	t.Helper()
+
+	return wafrWorkloadModel{
+		ID:          types.Int64Unknown(),
+		LastUpdated: types.StringUnknown(),
+		ProjectID:   types.Int64Value(1),
+		Name:        types.StringValue("checkout"),
+		Environment: types.StringValue(wafrEnvironmentProduction),
+		Regions:     testSet(t, types.StringType, []string{"us-east-1", "eu-west-1"}),
+		Lenses:      testSet(t, types.StringType, []string{wafrDefaultLens}),
+		Owners:      testSet(t, types.StringType, []string{"platform@example.com"}),
+		WorkloadArn: types.StringUnknown(),
+	}
+}
+
+func TestWAFRWorkloadResourceValidateConfig(t *testing.T) {
+	cases := map[string]struct {
+		update         func(*wafrWorkloadModel)
+		expectedErrors int
+	}{
+		"valid": {update: func(m *wafrWorkloadModel) {}},
+		"lens arn": {update: func(m *wafrWorkloadModel) {
+			m.Lenses = testSet(t, types.StringType, []string{"serverless", "arn:aws:wellarchitected:us-east-1:580010171808:lens/0123456789abcdef"})
+		}},
+		"short name":          {update: func(m *wafrWorkloadModel) { m.Name = types.StringValue("ab") }, expectedErrors: 1},
+		"invalid environment": {update: func(m *wafrWorkloadModel) { m.Environment = types.StringValue("PRODUCTION") }, expectedErrors: 1},
+		"invalid region":      {update: func(m *wafrWorkloadModel) { m.Regions = testSet(t, types.StringType, []string{"us-east"}) }, expectedErrors: 1},
+		"no region":           {update: func(m *wafrWorkloadModel) { m.Regions = testSet(t, types.StringType, []string{}) }, expectedErrors: 1},
+		"invalid lens":        {update: func(m *wafrWorkloadModel) { m.Lenses = testSet(t, types.StringType, []string{"Serverless Lens"}) }, expectedErrors: 1},
+		"invalid owner":       {update: func(m *wafrWorkloadModel) { m.Owners = testSet(t, types.StringType, []string{"platform"}) }, expectedErrors: 1},
+		"unknown owners":      {update: func(m *wafrWorkloadModel) { m.Owners = types.SetUnknown(types.StringType) }},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, s := newTestResource(t, newFakeNops(), &wafrWorkloadResource{})
+			config := testWAFRWorkloadModel(t)
+			c.update(&config)
+
+			var resp fwresource.ValidateConfigResponse
+			r.ValidateConfig(context.Background(), fwresource.ValidateConfigRequest{Config: tfsdk.Config(testResourceState(t, s, &config))}, &resp)
+			if resp.Diagnostics.ErrorsCount() != c.expectedErrors {
+				t.Errorf("expected %d errors, got %v", c.expectedErrors, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestWAFRWorkloadResourceLifecycle(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(Project{ID: 1, AccountNumber: "580010171808"})
+	r, s := newTestResource(t, fake, &wafrWorkloadResource{})
+
+	plan := testWAFRWorkloadModel(t)
+	createResp := fwresource.CreateResponse{State: nullResourceState(s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan))}, &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error creating WAFR workload: %v", createResp.Diagnostics)
+	}
+
+	var state wafrWorkloadModel
+	createResp.Diagnostics.Append(createResp.State.Get(ctx, &state)...)
+	if state.ID.ValueInt64() != 2 || state.WorkloadArn.ValueString() != "arn:aws:wellarchitected:us-east-1:580010171808:workload/00000000000000000000000000000002" {
+		t.Errorf("expected workload 2 defined in the Well-Architected Tool, got %s with %s", state.ID, state.WorkloadArn)
+	}
+
+	plan = state
+	plan.Environment = types.StringValue(wafrEnvironmentPreproduction)
+	plan.Owners = testSet(t, types.StringType, []string{"platform@example.com", "security@example.com"})
+	updateResp := fwresource.UpdateResponse{State: createResp.State}
+	r.Update(ctx, fwresource.UpdateRequest{Plan: tfsdk.Plan(testResourceState(t, s, &plan)), State: createResp.State}, &updateResp)
+	if updateResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error updating WAFR workload: %v", updateResp.Diagnostics)
+	}
+	if workload := fake.wafrWorkloads[2]; workload.Environment != wafrEnvironmentPreproduction || len(workload.Owners) != 2 {
+		t.Errorf("expected the workload to be updated in nOps, got %+v", workload)
+	}
+
+	deleteResp := fwresource.DeleteResponse{State: updateResp.State}
+	r.Delete(ctx, fwresource.DeleteRequest{State: updateResp.State}, &deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error deleting WAFR workload: %v", deleteResp.Diagnostics)
+	}
+	if len(fake.wafrWorkloads) != 0 {
+		t.Errorf("expected the workload to be deleted from nOps")
+	}
+}
+
+func TestWAFRWorkloadResourceImport(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeNops(Project{ID: 1, AccountNumber: "580010171808"})
+	fake.wafrWorkloads[7] = WAFRWorkload{
+		ID:          7,
+		Project:     1,
+		Name:        "checkout",
+		Environment: wafrEnvironmentProduction,
+		Regions:     []string{"us-east-1"},
+		Lenses:      []string{wafrDefaultLens, "serverless"},
+		Owners:      []string{"platform@example.com"},
+		WorkloadArn: "arn:aws:wellarchitected:us-east-1:580010171808:workload/checkout",
+	}
+	r, s := newTestResource(t, fake, &wafrWorkloadResource{})
+
+	importResp := fwresource.ImportStateResponse{State: nullResourceState(s)}
+	r.ImportState(ctx, fwresource.ImportStateRequest{ID: "1/7"}, &importResp)
+	resp := fwresource.ReadResponse{State: importResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: importResp.State}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error importing WAFR workload: %v", resp.Diagnostics)
+	}
+
+	var state wafrWorkloadModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if state.Name.ValueString() != "checkout" || !state.Lenses.Equal(testSet(t, types.StringType, []string{"serverless", wafrDefaultLens})) {
+		t.Errorf("expected the imported workload to be refreshed, got %s reviewed with %s", state.Name, state.Lenses)
+	}
+
+	// Workloads of another project aren't found.
+	wrongResp := fwresource.ImportStateResponse{State: nullResourceState(s)}
+	r.ImportState(ctx, fwresource.ImportStateRequest{ID: "2/7"}, &wrongResp)
+	r.Read(ctx, fwresource.ReadRequest{State: wrongResp.State}, &resp)
+	if resp.Diagnostics.HasError() || !resp.State.Raw.IsNull() {
+		t.Errorf("expected the workload of another project to be removed from state, got %v", resp.Diagnostics)
+	}
+}
@@ -3,8 +3,11 @@ package nops
 import (
 	"context"
 	"os"
+	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
@@ -15,7 +18,9 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ provider.Provider = &nopsIntegrationProvider{}
+	_ provider.Provider                       = &nopsIntegrationProvider{}
+	_ provider.ProviderWithFunctions          = &nopsIntegrationProvider{}
+	_ provider.ProviderWithEphemeralResources = &nopsIntegrationProvider{}
 )
 
 // New is a helper function to simplify provider server and testing implementation.
@@ -29,8 +34,12 @@ func New(version string) func() provider.Provider {
 
 // nopsIntegrationProviderModel maps provider schema data to a Go type.
 type nopsIntegrationProviderModel struct {
-	ApiKey types.String `tfsdk:"nops_api_key"`
-	Host   types.String `tfsdk:"nops_host"`
+	ApiKey            types.String  `tfsdk:"nops_api_key"`
+	Host              types.String  `tfsdk:"nops_host"`
+	MaxConcurrency    types.Int64   `tfsdk:"max_concurrency"`
+	RequestsPerSecond types.Float64 `tfsdk:"requests_per_second"`
+	Burst             types.Int64   `tfsdk:"burst"`
+	DriftReport       types.Bool    `tfsdk:"drift_report"`
 }
 
 // nopsIntegrationProvider is the provider implementation.
@@ -61,6 +70,24 @@ func (p *nopsIntegrationProvider) Schema(_ context.Context, _ provider.SchemaReq
 				Optional:    true,
 				Description: "nOps API URL, may also be provided with an environment variable NOPS_HOST.",
 			},
+			"max_concurrency": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of concurrent requests issued by bulk operations such as organization onboarding, defaults to 5. May also be provided with an environment variable NOPS_MAX_CONCURRENCY.",
+			},
+			"requests_per_second": schema.Float64Attribute{
+				Optional: true,
+				Description: "Sustained rate of requests sent to the nOps APIs by every resource of the provider, defaults to 10. " +
+					"The rate is automatically reduced while nOps answers with 429 responses. May also be provided with an environment variable NOPS_REQUESTS_PER_SECOND.",
+			},
+			"burst": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of requests that can be sent at once before being rate limited, defaults to 10. May also be provided with an environment variable NOPS_BURST.",
+			},
+			"drift_report": schema.BoolAttribute{
+				Optional: true,
+				Description: "Report attributes changed in the nOps UI outside of Terraform as warnings when refreshing resources, including who made the change when known. " +
+					"May also be provided with an environment variable NOPS_DRIFT_REPORT.",
+			},
 		},
 	}
 }
@@ -96,6 +123,42 @@ func (p *nopsIntegrationProvider) Configure(ctx context.Context, req provider.Co
 		)
 	}
 
+	if config.MaxConcurrency.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_concurrency"),
+			"Unknown nOps max concurrency",
+			"The provider cannot create the nOps API client as there is an unknown configuration value for the maximum concurrency. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the NOPS_MAX_CONCURRENCY environment variable.",
+		)
+	}
+
+	if config.RequestsPerSecond.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("requests_per_second"),
+			"Unknown nOps requests per second",
+			"The provider cannot create the nOps API client as there is an unknown configuration value for the requests per second. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the NOPS_REQUESTS_PER_SECOND environment variable.",
+		)
+	}
+
+	if config.DriftReport.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("drift_report"),
+			"Unknown nOps drift report",
+			"The provider cannot create the nOps API client as there is an unknown configuration value for the drift report. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the NOPS_DRIFT_REPORT environment variable.",
+		)
+	}
+
+	if config.Burst.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("burst"),
+			"Unknown nOps burst",
+			"The provider cannot create the nOps API client as there is an unknown configuration value for the burst. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the NOPS_BURST environment variable.",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -114,6 +177,78 @@ func (p *nopsIntegrationProvider) Configure(ctx context.Context, req provider.Co
 		host = config.Host.ValueString()
 	}
 
+	maxConcurrency := int64(DefaultMaxConcurrency)
+	if env := os.Getenv("NOPS_MAX_CONCURRENCY"); env != "" {
+		value, err := strconv.ParseInt(env, 10, 64)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_concurrency"),
+				"Invalid nOps max concurrency",
+				"The NOPS_MAX_CONCURRENCY environment variable must be a whole number: "+err.Error(),
+			)
+		} else {
+			maxConcurrency = value
+		}
+	}
+
+	if !config.MaxConcurrency.IsNull() {
+		maxConcurrency = config.MaxConcurrency.ValueInt64()
+	}
+
+	requestsPerSecond := DefaultRequestsPerSecond
+	if env := os.Getenv("NOPS_REQUESTS_PER_SECOND"); env != "" {
+		value, err := strconv.ParseFloat(env, 64)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("requests_per_second"),
+				"Invalid nOps requests per second",
+				"The NOPS_REQUESTS_PER_SECOND environment variable must be a number: "+err.Error(),
+			)
+		} else {
+			requestsPerSecond = value
+		}
+	}
+
+	if !config.RequestsPerSecond.IsNull() {
+		requestsPerSecond = config.RequestsPerSecond.ValueFloat64()
+	}
+
+	burst := int64(DefaultBurst)
+	if env := os.Getenv("NOPS_BURST"); env != "" {
+		value, err := strconv.ParseInt(env, 10, 64)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("burst"),
+				"Invalid nOps burst",
+				"The NOPS_BURST environment variable must be a whole number: "+err.Error(),
+			)
+		} else {
+			burst = value
+		}
+	}
+
+	if !config.Burst.IsNull() {
+		burst = config.Burst.ValueInt64()
+	}
+
+	driftReport := false
+	if env := os.Getenv("NOPS_DRIFT_REPORT"); env != "" {
+		value, err := strconv.ParseBool(env)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("drift_report"),
+				"Invalid nOps drift report",
+				"The NOPS_DRIFT_REPORT environment variable must be a boolean: "+err.Error(),
+			)
+		} else {
+			driftReport = value
+		}
+	}
+
+	if !config.DriftReport.IsNull() {
+		driftReport = config.DriftReport.ValueBool()
+	}
+
 	if apiKey == "" {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("apiKey"),
@@ -128,6 +263,30 @@ func (p *nopsIntegrationProvider) Configure(ctx context.Context, req provider.Co
 		host = HostURL
 	}
 
+	if maxConcurrency < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_concurrency"),
+			"Invalid nOps max concurrency",
+			"The maximum concurrency must be at least 1.",
+		)
+	}
+
+	if requestsPerSecond <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("requests_per_second"),
+			"Invalid nOps requests per second",
+			"The requests per second must be greater than 0.",
+		)
+	}
+
+	if burst < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("burst"),
+			"Invalid nOps burst",
+			"The burst must be at least 1.",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -148,11 +307,15 @@ func (p *nopsIntegrationProvider) Configure(ctx context.Context, req provider.Co
 		)
 		return
 	}
+	client.MaxConcurrency = int(maxConcurrency)
+	client.Limiter = NewRateLimiter(requestsPerSecond, int(burst))
+	client.DriftReport = driftReport
 
-	// Make the nOps client available during DataSource and Resource
-	// type Configure methods.
+	// Make the nOps client available during DataSource, Resource and
+	// EphemeralResource type Configure methods.
 	resp.DataSourceData = client
 	resp.ResourceData = client
+	resp.EphemeralResourceData = client
 
 	tflog.Info(ctx, "Configured nOps client", map[string]any{"success": true})
 
@@ -162,6 +325,7 @@ func (p *nopsIntegrationProvider) Configure(ctx context.Context, req provider.Co
 func (p *nopsIntegrationProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewProjectsDataSource,
+		NewWAFRWorkloadDataSource,
 	}
 }
 
@@ -170,5 +334,34 @@ func (p *nopsIntegrationProvider) Resources(_ context.Context) []func() resource
 	return []func() resource.Resource{
 		NewProjectResource,
 		NewProjectIntegrationResource,
+		NewOrganizationOnboardingResource,
+		NewCURConfigurationResource,
+		NewSystemBucketResource,
+		NewAzureProjectResource,
+		NewAzureIntegrationResource,
+		NewGCPProjectResource,
+		NewEKSClusterIntegrationResource,
+		NewComputeCopilotResource,
+		NewCommitmentManagementResource,
+		NewWAFRWorkloadResource,
+		NewCostAllocationTagsResource,
+	}
+}
+
+// EphemeralResources defines the ephemeral resources implemented in the provider.
+func (p *nopsIntegrationProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewExternalIDEphemeralResource,
+		NewAPITokenEphemeralResource,
+	}
+}
+
+// Functions defines the functions implemented in the provider.
+func (p *nopsIntegrationProvider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewIntegrationRoleNameFunction,
+		NewSystemBucketNameFunction,
+		NewParseRoleArnFunction,
+		NewIsPayerFunction,
 	}
 }
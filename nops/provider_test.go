@@ -1,11 +1,16 @@
 package nops
 
 import (
+	"context"
 	"fmt"
+	"net/http/httptest"
 	"os"
+	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 var (
@@ -27,3 +32,48 @@ provider "nops" {
 }`, nops_api_key,
 	)
 )
+
+// newTestProviderServer returns a provider server configured with the fake nOps APIs, used to test what only
+// the framework can set up such as the private data of ephemeral resources.
+func newTestProviderServer(t *testing.T, fake *fakeNops) tfprotov6.ProviderServer {
+	t.Helper()
+	ctx := context.Background()
+
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	p := New("test")()
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+	configType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	values := map[string]tftypes.Value{}
+	for name, attributeType := range configType.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+	values["nops_api_key"] = tftypes.NewValue(tftypes.String, "test")
+	values["nops_host"] = tftypes.NewValue(tftypes.String, server.URL)
+
+	providerServer, err := providerserver.NewProtocol6WithError(p)()
+	if err != nil {
+		t.Fatalf("unexpected error creating provider server: %s", err)
+	}
+	config := testDynamicValue(t, configType, values)
+	resp, err := providerServer.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{TerraformVersion: "1.10.0", Config: &config})
+	if err != nil || len(resp.Diagnostics) > 0 {
+		t.Fatalf("unexpected error configuring provider: %v %v", err, resp.Diagnostics)
+	}
+
+	return providerServer
+}
+
+// testDynamicValue encodes the object values as sent by Terraform.
+func testDynamicValue(t *testing.T, objectType tftypes.Object, values map[string]tftypes.Value) tfprotov6.DynamicValue {
+	t.Helper()
+
+	value, err := tfprotov6.NewDynamicValue(objectType, tftypes.NewValue(objectType, values))
+	if err != nil {
+		t.Fatalf("unexpected error encoding value: %s", err)
+	}
+
+	return value
+}
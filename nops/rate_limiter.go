@@ -0,0 +1,154 @@
+package nops
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// DefaultRequestsPerSecond - Default sustained rate of requests sent to the nOps APIs.
+const DefaultRequestsPerSecond float64 = 10
+
+// DefaultBurst - Default number of requests that can be sent at once before being rate limited.
+const DefaultBurst int = 10
+
+// maxThrottlePause - Longest pause honored from the Retry-After header of a 429 response.
+const maxThrottlePause = 30 * time.Second
+
+// RateLimiter - token bucket shared by every request of a client. The rate is halved each time nOps
+// answers with 429 Too Many Requests and recovers gradually on successful responses.
+type RateLimiter struct {
+	mu          sync.Mutex
+	baseRate    float64
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+
+	requests  int64
+	throttled int64
+	waited    time.Duration
+}
+
+// NewRateLimiter - instantiates a rate limiter allowing requestsPerSecond with bursts of up to burst requests.
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		baseRate: requestsPerSecond,
+		rate:     requestsPerSecond,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		last:     time.Now(),
+	}
+}
+
+// Wait blocks until a request can be sent, or returns the context error when it's canceled first.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	// Reserve the token up front, a negative balance queues the following callers behind this one.
+	l.tokens--
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	if pause := l.pausedUntil.Sub(now); pause > wait {
+		wait = pause
+	}
+
+	l.requests++
+	l.waited += wait
+	fields := l.metrics()
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	fields["wait_ms"] = wait.Milliseconds()
+	tflog.Debug(ctx, "Waiting for nOps API rate limiter", fields)
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Give the reserved token back, the request is never sent.
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Throttled slows the limiter down after a 429 response, pausing every request for retryAfter when provided,
+// up to maxThrottlePause.
+func (l *RateLimiter) Throttled(ctx context.Context, retryAfter time.Duration) {
+	if l == nil {
+		return
+	}
+	if retryAfter > maxThrottlePause {
+		retryAfter = maxThrottlePause
+	}
+
+	l.mu.Lock()
+	l.throttled++
+	l.rate /= 2
+	if minRate := l.baseRate / 10; l.rate < minRate {
+		l.rate = minRate
+	}
+	if l.tokens > 0 {
+		l.tokens = 0
+	}
+	if until := time.Now().Add(retryAfter); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+	fields := l.metrics()
+	l.mu.Unlock()
+
+	fields["retry_after_ms"] = retryAfter.Milliseconds()
+	tflog.Debug(ctx, "nOps API rate limit reached, slowing down requests", fields)
+}
+
+// Succeeded lets the limiter recover its configured rate after being throttled.
+func (l *RateLimiter) Succeeded(ctx context.Context) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rate >= l.baseRate {
+		return
+	}
+
+	l.rate += l.baseRate / 10
+	if l.rate > l.baseRate {
+		l.rate = l.baseRate
+	}
+	if l.rate == l.baseRate {
+		tflog.Debug(ctx, "nOps API rate limiter recovered its configured rate", l.metrics())
+	}
+}
+
+// metrics returns the limiter counters as log fields, must be called holding the lock.
+func (l *RateLimiter) metrics() map[string]any {
+	return map[string]any{
+		"requests":            l.requests,
+		"throttled":           l.throttled,
+		"total_wait_ms":       l.waited.Milliseconds(),
+		"requests_per_second": l.rate,
+	}
+}
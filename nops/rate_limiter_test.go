@@ -0,0 +1,86 @@
+package nops
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterBurst(t *testing.T) {
+	limiter := NewRateLimiter(20, 2)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error waiting: %s", err)
+		}
+	}
+
+	// The first two requests use the burst, the remaining two wait 50ms each.
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("expected requests over the burst to wait, took %s", elapsed)
+	}
+}
+
+func TestRateLimiterCanceled(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+	limiter.Throttled(context.Background(), time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := limiter.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected the wait to be interrupted by the context, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the wait to stop when the context is done, took %s", elapsed)
+	}
+}
+
+func TestRateLimiterThrottledPauseCapped(t *testing.T) {
+	limiter := NewRateLimiter(10, 1)
+
+	limiter.Throttled(context.Background(), time.Hour)
+	if pause := time.Until(limiter.pausedUntil); pause > maxThrottlePause {
+		t.Errorf("expected the pause to be capped to %s, got %s", maxThrottlePause, pause)
+	}
+}
+
+func TestRateLimiterAdaptive(t *testing.T) {
+	ctx := context.Background()
+	limiter := NewRateLimiter(10, 1)
+
+	limiter.Throttled(ctx, 0)
+	limiter.Throttled(ctx, 0)
+	if limiter.rate != 2.5 {
+		t.Errorf("expected rate to be halved twice to 2.5, got %v", limiter.rate)
+	}
+
+	for i := 0; i < 5; i++ {
+		limiter.Throttled(ctx, 0)
+	}
+	if limiter.rate != 1 {
+		t.Errorf("expected rate to be bounded to 1, got %v", limiter.rate)
+	}
+
+	for i := 0; i < 20; i++ {
+		limiter.Succeeded(ctx)
+	}
+	if limiter.rate != 10 {
+		t.Errorf("expected rate to recover to 10, got %v", limiter.rate)
+	}
+	if limiter.throttled != 7 {
+		t.Errorf("expected 7 throttled responses, got %d", limiter.throttled)
+	}
+}
+
+func TestRateLimiterNil(t *testing.T) {
+	var limiter *RateLimiter
+
+	ctx := context.Background()
+	if err := limiter.Wait(ctx); err != nil {
+		t.Errorf("unexpected error waiting: %s", err)
+	}
+	limiter.Throttled(ctx, time.Second)
+	limiter.Succeeded(ctx)
+}
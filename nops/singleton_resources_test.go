@@ -0,0 +1,103 @@
+package nops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+)
+
+// singletonResource describes a resource holding settings nOps keeps once per project, all of them replaced by a PUT.
+type singletonResource struct {
+	resource fwresource.ResourceWithConfigure
+	// path is the settings API of project 1.
+	path string
+	// plan returns a planned model of the resource for project 1.
+	plan func(t *testing.T) any
+	// configure stores settings configured outside of Terraform for project 1.
+	configure func(fake *fakeNops)
+}
+
+// singletonResources returns the resources holding the settings of a project.
+func singletonResources() map[string]singletonResource {
+	return map[string]singletonResource{
+		"nops_cur_configuration": {
+			resource: &curConfigurationResource{},
+			path:     "/c/admin/projectaws/1/cur_configuration/",
+			plan:     func(t *testing.T) any { model := testCURConfigurationModel(); return &model },
+			configure: func(fake *fakeNops) {
+				fake.curConfigurations[1] = CURConfiguration{BucketName: "console-cur", Prefix: "cur", ReportName: "console", Format: curFormatCUR2, Status: "active"}
+			},
+		},
+		"nops_compute_copilot": {
+			resource: &computeCopilotResource{},
+			path:     "/c/admin/projectaws/1/compute_copilot/",
+			plan:     func(t *testing.T) any { model := testComputeCopilotModel(t); return &model },
+			configure: func(fake *fakeNops) {
+				fake.computeCopilots[1] = ComputeCopilot{Enabled: true, AutoScalingGroups: []string{"console"}, SpotPercentage: 50, Status: "active"}
+			},
+		},
+		"nops_commitment_management": {
+			resource: &commitmentManagementResource{},
+			path:     "/c/admin/projectaws/1/commitment_management/",
+			plan:     func(t *testing.T) any { model := testCommitmentManagementModel(t); return &model },
+			configure: func(fake *fakeNops) {
+				fake.commitments[1] = CommitmentManagement{Enabled: true, RiskTolerance: "aggressive", TermLengths: []int64{3}, PaymentOptions: []string{"all_upfront"}}
+			},
+		},
+		"nops_cost_allocation_tags": {
+			resource: &costAllocationTagsResource{},
+			path:     "/c/admin/projectaws/1/cost_allocation_tags/",
+			plan:     func(t *testing.T) any { model := testCostAllocationTagsModel(t, "team"); return &model },
+			configure: func(fake *fakeNops) {
+				fake.costTags[1] = CostAllocationTags{
+					TagKeys: []string{"owner"},
+					Tags:    []CostAllocationTag{{Key: "owner", Status: costAllocationTagActive}},
+				}
+			},
+		},
+	}
+}
+
+func TestSingletonResourcesCreateExisting(t *testing.T) {
+	for name, c := range singletonResources() {
+		t.Run(name, func(t *testing.T) {
+			fake := newFakeNops(Project{ID: 1, AccountNumber: "580010171808"})
+			c.configure(fake)
+			r, s := newTestResource(t, fake, c.resource)
+
+			resp := fwresource.CreateResponse{State: nullResourceState(s)}
+			r.Create(context.Background(), fwresource.CreateRequest{Plan: tfsdk.Plan(testResourceState(t, s, c.plan(t)))}, &resp)
+			if !resp.Diagnostics.HasError() {
+				t.Errorf("expected an error creating settings that already exist in nOps")
+			}
+			if fake.requested("PUT", c.path) {
+				t.Errorf("expected the existing settings to be left untouched")
+			}
+		})
+	}
+}
+
+func TestSingletonResourcesReadRemoved(t *testing.T) {
+	for name, c := range singletonResources() {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			r, s := newTestResource(t, newFakeNops(Project{ID: 1, AccountNumber: "580010171808"}), c.resource)
+
+			state := nullResourceState(s)
+			if diags := state.SetAttribute(ctx, path.Root("id"), int64(1)); diags.HasError() {
+				t.Fatalf("unexpected error building state: %v", diags)
+			}
+			resp := fwresource.ReadResponse{State: state}
+			r.Read(ctx, fwresource.ReadRequest{State: state}, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error reading settings: %v", resp.Diagnostics)
+			}
+			if !resp.State.Raw.IsNull() {
+				t.Errorf("expected the settings removed in nOps to be removed from state")
+			}
+		})
+	}
+}